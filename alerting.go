@@ -3,12 +3,15 @@ package neustar
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/google/go-querystring/query"
 )
 
 const (
-	// AlertURI is the endpoint for calls to the scripting API
+	// AlertURI is the endpoint for calls to the alerting API
 	AlertURI = "alert/1.0"
 
 	// PolicyURI is the endpoint for policy calls
@@ -21,55 +24,116 @@ var Strikes = []int{1, 2, 3}
 // NewAlertPolicyParameters holds the parameters needed to pass to the NewAlertPolicy method
 type NewAlertPolicyParameters struct {
 	// Name of the alert policy
-	Name string
+	Name string `json:"name"`
 
 	// EmailAddresses is a string slice of comma-separated email addresses associated with
 	// the alert policy (e.g. ["alert@mycompany.com","myemail@gmail.com"].
-	EmailAddresses []string
+	EmailAddresses []string `json:"emailAddresses"`
+
+	// Strikes before triggering an alert.
+	Strikes int `json:"strikes"`
+
+	// Description for the alert policy
+	Description string `json:"description"`
+
+	// Conditions that make this an advanced alert policy
+	AdvancedRules *AdvancedAlertRules `json:"advancedRules,omitempty"`
+
+	AlertRouting
+}
+
+// UpdateAlertPolicyParameters holds the parameters that can be changed on an
+// existing alert policy. Empty fields are left unchanged.
+type UpdateAlertPolicyParameters struct {
+	// Name of the alert policy
+	Name string `json:"name,omitempty"`
+
+	// EmailAddresses associated with the alert policy. This replaces the
+	// existing list.
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
 
 	// Strikes before triggering an alert.
-	Strikes int
+	Strikes int `json:"strikes,omitempty"`
 
 	// Description for the alert policy
-	Description string
+	Description string `json:"description,omitempty"`
+
+	// Conditions of an advanced alert policy, replacing the existing ones
+	AdvancedRules *AdvancedAlertRules `json:"advancedRules,omitempty"`
+
+	AlertRouting
+}
+
+// AlertPolicyListParameters holds the allowed options for listing alert policies
+type AlertPolicyListParameters struct {
+	// From which position in the return list you wish to start
+	Offset int `url:"offset"`
 }
 
-// NewAlertPolicyResponse
+// NewAlertPolicyResponse holds the response from the NewAlertPolicy call
 type NewAlertPolicyResponse struct {
+	// ID of the new alert policy
+	ID string `json:"id"`
+
 	// Name of the alert policy
-	Name string
+	Name string `json:"name"`
 
 	// EmailAddresses associated with the alert policy
-	EmailAddresses []string
+	EmailAddresses []string `json:"emailAddresses"`
 
 	// Description of the alert policy
-	Description string
+	Description string `json:"description"`
 
 	// Strikes for the alert policy
-	Strikes int
+	Strikes int `json:"strikes"`
+
+	// Conditions of the advanced alert policy
+	AdvancedRules *AdvancedAlertRules `json:"advancedRules,omitempty"`
+
+	AlertRouting
 }
 
-// ListAlertPoliciesResponse holds the response from the ListAlertPolicies call
-type ListAlertPoliciesResponse struct {
+// AlertPolicy holds the alert policy data
+type AlertPolicy struct {
 	// ID of the alert policy
-	ID string
+	ID string `json:"id"`
 
 	// Name of the alert policy
-	Name string
+	Name string `json:"name"`
 
 	// EmailAddresses is a slice of strings containing email addresses associated
 	// with the alert policy
-	EmailAddresses []string
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
 
 	// The description of the alert policy
-	Description string
+	Description string `json:"description,omitempty"`
 
 	// Strikes before triggering an alert.
-	Strikes int
+	Strikes int `json:"strikes,omitempty"`
 
 	// AdvancedEdit holds a flag that will return true if the policy is an Advanced
 	// Alert Policy
-	AdvancedEdit bool
+	AdvancedEdit bool `json:"advancedEdit,omitempty"`
+
+	// Conditions of the policy, set when AdvancedEdit is true
+	AdvancedRules *AdvancedAlertRules `json:"advancedRules,omitempty"`
+
+	AlertRouting
+}
+
+// ListAlertPoliciesResponse holds the response from the ListAlertPolicies call
+//
+// Deprecated: use AlertPolicy.
+type ListAlertPoliciesResponse = AlertPolicy
+
+// AlertPolicyDataResponse holds the return from the API list call
+type AlertPolicyDataResponse struct {
+	Data struct {
+		Total  int           `json:"total"`
+		Offset int           `json:"offset"`
+		More   bool          `json:"more"`
+		Items  []AlertPolicy `json:"items"`
+	} `json:"data"`
 }
 
 // Alerting holds alerting config
@@ -77,17 +141,38 @@ type Alerting struct {
 	neustar *Neustar
 }
 
+// NewAlerting creates a new Alerting object
+func NewAlerting(neustar *Neustar) *Alerting {
+	return &Alerting{
+		neustar: neustar,
+	}
+}
+
 // NewAlertPolicy creates a new Alert policy
 func (a *Alerting) NewAlertPolicy(napp *NewAlertPolicyParameters) (NewAlertPolicyResponse, error) {
+	if napp == nil {
+		return NewAlertPolicyResponse{}, errors.New("neustar: alert policy parameters are required")
+	}
+	if !ValidStrikes(napp.Strikes) {
+		return NewAlertPolicyResponse{}, fmt.Errorf("neustar: %d is not a valid number of strikes", napp.Strikes)
+	}
+	if err := napp.AlertRouting.Validate(); err != nil {
+		return NewAlertPolicyResponse{}, err
+	}
+	if napp.AdvancedRules != nil {
+		if err := napp.AdvancedRules.Validate(); err != nil {
+			return NewAlertPolicyResponse{}, err
+		}
+	}
 	buffer, err := json.Marshal(napp)
 	if err != nil {
 		return NewAlertPolicyResponse{}, err
 	}
 	body := bytes.NewBuffer(buffer)
-	var data NewAlertPolicyResponse
+	var data map[string]map[string]NewAlertPolicyResponse
 	request, err := http.NewRequest(
 		"POST",
-		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", BaseURL, AlertURI, PolicyURI, a.neustar.Key, a.neustar.DigitalSignature()),
+		fmt.Sprintf("%s%s%s?apikey=%s&sig=%s", a.neustar.baseURL(), AlertURI, PolicyURI, a.neustar.Key, a.neustar.DigitalSignature()),
 		body)
 	if err != nil {
 		return NewAlertPolicyResponse{}, err
@@ -99,25 +184,142 @@ func (a *Alerting) NewAlertPolicy(napp *NewAlertPolicyParameters) (NewAlertPolic
 		return NewAlertPolicyResponse{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return NewAlertPolicyResponse{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return NewAlertPolicyResponse{}, err
 	}
-	return data, nil
+	return data["data"]["items"], nil
+}
+
+// ListAlertPolicies pages through and retrieves every alert policy ordered by
+// date in descending order
+func (a *Alerting) ListAlertPolicies() ([]AlertPolicy, error) {
+	var policies []AlertPolicy
+	err := pageAll(func(offset int) (int, bool, error) {
+		page, more, err := a.ListAlertPoliciesPage(&AlertPolicyListParameters{Offset: offset})
+		policies = append(policies, page...)
+		return len(page), more, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// ListAlertPoliciesPage retrieves a page of policies ordered by date in
+// descending order. If more is true, make another call with the offset set to
+// the number of results returned so far.
+func (a *Alerting) ListAlertPoliciesPage(alp *AlertPolicyListParameters) ([]AlertPolicy, bool, error) {
+	if alp == nil {
+		alp = &AlertPolicyListParameters{}
+	}
+	v, err := query.Values(alp)
+	if err != nil {
+		return nil, false, err
+	}
+	var response *http.Response
+	var data AlertPolicyDataResponse
+	response, err = http.Get(fmt.Sprintf(
+		"%s%s%s?%s&apikey=%s&sig=%s",
+		a.neustar.baseURL(), AlertURI, PolicyURI, v.Encode(), a.neustar.Key, a.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, false, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, false, err
+	}
+	return data.Data.Items, data.Data.More, nil
 }
 
-// ListAlertPolicies retrieves a list of policies ordered by date in descending order.
-func (a *Alerting) ListAlertPolicies() (ListAlertPoliciesResponse, error) {
+// GetAlertPolicy retrieves the alert policy with the given ID
+func (a *Alerting) GetAlertPolicy(id string) (AlertPolicy, error) {
 	var response *http.Response
-	var data ListAlertPoliciesResponse
-	response, err := http.Get(fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", BaseURL, AlertURI, PolicyURI, a.neustar.Key, a.neustar.DigitalSignature()))
+	var data map[string]map[string]AlertPolicy
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s%s/%s?apikey=%s&sig=%s",
+		a.neustar.baseURL(), AlertURI, PolicyURI, id, a.neustar.Key, a.neustar.DigitalSignature()))
 	if err != nil {
-		return ListAlertPoliciesResponse{}, err
+		return AlertPolicy{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return AlertPolicy{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
-		return ListAlertPoliciesResponse{}, err
+		return AlertPolicy{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// UpdateAlertPolicy changes some or all of the parameters of an existing alert
+// policy and returns the policy after the update.
+func (a *Alerting) UpdateAlertPolicy(id string, uapp *UpdateAlertPolicyParameters) (AlertPolicy, error) {
+	if uapp == nil {
+		return AlertPolicy{}, errors.New("neustar: alert policy parameters are required")
+	}
+	if uapp.Strikes != 0 && !ValidStrikes(uapp.Strikes) {
+		return AlertPolicy{}, fmt.Errorf("neustar: %d is not a valid number of strikes", uapp.Strikes)
+	}
+	if err := uapp.AlertRouting.Validate(); err != nil {
+		return AlertPolicy{}, err
+	}
+	if uapp.AdvancedRules != nil {
+		if err := uapp.AdvancedRules.Validate(); err != nil {
+			return AlertPolicy{}, err
+		}
 	}
-	return data, nil
+	buffer, err := json.Marshal(uapp)
+	if err != nil {
+		return AlertPolicy{}, err
+	}
+	body := bytes.NewBuffer(buffer)
+	var data map[string]map[string]AlertPolicy
+	request, err := http.NewRequest(
+		"PUT",
+		fmt.Sprintf("%s%s%s/%s?apikey=%s&sig=%s", a.neustar.baseURL(), AlertURI, PolicyURI, id, a.neustar.Key, a.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		return AlertPolicy{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return AlertPolicy{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return AlertPolicy{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return AlertPolicy{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// DeleteAlertPolicy deletes the alert policy with the given ID and returns the
+// status code of the response. Error statuses are returned as a ResponseError.
+func (a *Alerting) DeleteAlertPolicy(id string) (int, error) {
+	request, err := http.NewRequest(
+		"DELETE",
+		fmt.Sprintf("%s%s%s/%s?apikey=%s&sig=%s", a.neustar.baseURL(), AlertURI, PolicyURI, id, a.neustar.Key, a.neustar.DigitalSignature()),
+		nil)
+	if err != nil {
+		return 0, err
+	}
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	return response.StatusCode, checkResponse(response)
 }
 
 // ValidStrikes makes sure that the given strike is valid
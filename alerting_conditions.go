@@ -0,0 +1,229 @@
+package neustar
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// ConditionLoadTime fires when the load time is above the threshold at
+	// N of M locations
+	ConditionLoadTime = "loadTime"
+
+	// ConditionConsecutiveFailures fires when a location fails the given
+	// number of samples in a row
+	ConditionConsecutiveFailures = "consecutiveFailures"
+
+	// ConditionStepLoadTime fires when the load time of a single step is
+	// above the threshold
+	ConditionStepLoadTime = "stepLoadTime"
+
+	// ConditionDNSMismatch fires when a lookup resolves to an address that
+	// is not expected
+	ConditionDNSMismatch = "dnsMismatch"
+)
+
+// ConditionTypes is a slice of valid advanced alert condition types
+var ConditionTypes = []string{
+	ConditionLoadTime,
+	ConditionConsecutiveFailures,
+	ConditionStepLoadTime,
+	ConditionDNSMismatch,
+}
+
+// ConditionMatches is a slice of valid ways conditions are combined
+var ConditionMatches = []string{"all", "any"}
+
+// AlertCondition holds a single rule of an advanced alert policy
+type AlertCondition struct {
+	// The type of the condition, one of ConditionTypes
+	Type string `json:"type"`
+
+	// The load time threshold in milliseconds
+	Threshold int `json:"threshold,omitempty"`
+
+	// The number of locations that must breach the threshold
+	Locations int `json:"locations,omitempty"`
+
+	// The number of locations considered
+	OutOf int `json:"outOf,omitempty"`
+
+	// The number of failed samples in a row at a location
+	Strikes int `json:"strikes,omitempty"`
+
+	// The step the threshold applies to, starting at 1
+	Step int `json:"step,omitempty"`
+
+	// The hostname that is looked up
+	Hostname string `json:"hostname,omitempty"`
+
+	// The addresses the hostname is expected to resolve to
+	ExpectedIPs []string `json:"expectedIps,omitempty"`
+}
+
+// LoadTimeAbove creates a condition that fires when the load time is above
+// the given threshold at n of m locations
+func LoadTimeAbove(threshold time.Duration, n, m int) AlertCondition {
+	return AlertCondition{
+		Type:      ConditionLoadTime,
+		Threshold: int(threshold / time.Millisecond),
+		Locations: n,
+		OutOf:     m,
+	}
+}
+
+// ConsecutiveFailures creates a condition that fires when a location fails
+// the given number of samples in a row
+func ConsecutiveFailures(strikes int) AlertCondition {
+	return AlertCondition{
+		Type:    ConditionConsecutiveFailures,
+		Strikes: strikes,
+	}
+}
+
+// StepLoadTimeAbove creates a condition that fires when the load time of the
+// given step is above the given threshold
+func StepLoadTimeAbove(step int, threshold time.Duration) AlertCondition {
+	return AlertCondition{
+		Type:      ConditionStepLoadTime,
+		Step:      step,
+		Threshold: int(threshold / time.Millisecond),
+	}
+}
+
+// DNSMismatch creates a condition that fires when the given hostname resolves
+// to an address other than the expected ones
+func DNSMismatch(hostname string, expectedIPs ...string) AlertCondition {
+	return AlertCondition{
+		Type:        ConditionDNSMismatch,
+		Hostname:    hostname,
+		ExpectedIPs: expectedIPs,
+	}
+}
+
+// Validate makes sure the condition has the fields its type requires. The
+// given number of locations is used to bound N of M conditions; zero skips
+// that check.
+func (c AlertCondition) Validate(locations int) error {
+	if !ValidConditionType(c.Type) {
+		return fmt.Errorf("neustar: %s is not a valid condition type", c.Type)
+	}
+	switch c.Type {
+	case ConditionLoadTime:
+		if c.Threshold <= 0 {
+			return errors.New("neustar: load time condition requires a threshold")
+		}
+		if c.Locations < 1 || c.OutOf < c.Locations {
+			return fmt.Errorf("neustar: load time condition requires 1 <= N <= M, got %d of %d", c.Locations, c.OutOf)
+		}
+		if locations > 0 && c.OutOf > locations {
+			return fmt.Errorf("neustar: load time condition considers %d locations but only %d are configured", c.OutOf, locations)
+		}
+	case ConditionConsecutiveFailures:
+		if !ValidStrikes(c.Strikes) {
+			return fmt.Errorf("neustar: %d is not a valid number of strikes", c.Strikes)
+		}
+	case ConditionStepLoadTime:
+		if c.Step < 1 {
+			return errors.New("neustar: step load time condition requires a step")
+		}
+		if c.Threshold <= 0 {
+			return errors.New("neustar: step load time condition requires a threshold")
+		}
+	case ConditionDNSMismatch:
+		if c.Hostname == "" {
+			return errors.New("neustar: DNS mismatch condition requires a hostname")
+		}
+		if len(c.ExpectedIPs) == 0 {
+			return errors.New("neustar: DNS mismatch condition requires expected addresses")
+		}
+		for _, ip := range c.ExpectedIPs {
+			if net.ParseIP(ip) == nil {
+				return fmt.Errorf("neustar: %s is not a valid IP address", ip)
+			}
+		}
+	}
+	return nil
+}
+
+// conditionError wraps the validation error of a single condition with its
+// position in the rules
+type conditionError struct {
+	index int
+	err   error
+}
+
+// Error returns the condition error prefixed with its position
+func (e *conditionError) Error() string {
+	return fmt.Sprintf("neustar: condition %d: %s", e.index, strings.TrimPrefix(e.err.Error(), "neustar: "))
+}
+
+// Unwrap returns the error of the condition
+func (e *conditionError) Unwrap() error {
+	return e.err
+}
+
+// AdvancedAlertRules holds the conditions of an advanced alert policy
+type AdvancedAlertRules struct {
+	// Whether 'all' or 'any' of the conditions must be met. Defaults to 'any'.
+	Match string `json:"match,omitempty"`
+
+	// The locations the conditions are evaluated for. Empty means all of
+	// the monitor's locations.
+	Locations []string `json:"locations,omitempty"`
+
+	Conditions []AlertCondition `json:"conditions"`
+}
+
+// NewAdvancedAlertRules creates a new AdvancedAlertRules object matching any
+// of the given conditions
+func NewAdvancedAlertRules(conditions ...AlertCondition) *AdvancedAlertRules {
+	return &AdvancedAlertRules{
+		Match:      "any",
+		Conditions: conditions,
+	}
+}
+
+// Validate makes sure the locations, match and every condition are valid
+func (r *AdvancedAlertRules) Validate() error {
+	if len(r.Conditions) == 0 {
+		return errors.New("neustar: advanced alert rules require at least one condition")
+	}
+	if r.Match != "" && !ValidConditionMatch(r.Match) {
+		return fmt.Errorf("neustar: %s is not a valid match", r.Match)
+	}
+	for _, location := range r.Locations {
+		if !ValidLocation(location) {
+			return fmt.Errorf("neustar: %s is not a valid location", location)
+		}
+	}
+	for i, condition := range r.Conditions {
+		if err := condition.Validate(len(r.Locations)); err != nil {
+			return &conditionError{index: i + 1, err: err}
+		}
+	}
+	return nil
+}
+
+// ValidConditionType validates the given condition type is valid
+func ValidConditionType(conditionType string) bool {
+	for _, i := range ConditionTypes {
+		if i == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidConditionMatch validates the given match is valid
+func ValidConditionMatch(match string) bool {
+	for _, i := range ConditionMatches {
+		if i == match {
+			return true
+		}
+	}
+	return false
+}
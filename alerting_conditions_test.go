@@ -0,0 +1,57 @@
+package neustar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAdvancedAlertRulesValidate
+func TestAdvancedAlertRulesValidate(t *testing.T) {
+	t.Parallel()
+
+	rules := NewAdvancedAlertRules(
+		LoadTimeAbove(5*time.Second, 2, 3),
+		ConsecutiveFailures(2),
+		StepLoadTimeAbove(2, 3*time.Second),
+		DNSMismatch("www.example.com", "192.0.2.10", "2001:db8::1"),
+	)
+	rules.Locations = []string{"dallas", "london", "tokyo"}
+	if err := rules.Validate(); err != nil {
+		t.Error(err)
+	}
+	if rules.Conditions[0].Threshold != 5000 {
+		t.Errorf("expected threshold of 5000ms, got %d", rules.Conditions[0].Threshold)
+	}
+
+	invalid := map[string]*AdvancedAlertRules{
+		"no conditions":     NewAdvancedAlertRules(),
+		"bad match":         {Match: "some", Conditions: []AlertCondition{ConsecutiveFailures(1)}},
+		"bad location":      {Locations: []string{"atlantis"}, Conditions: []AlertCondition{ConsecutiveFailures(1)}},
+		"bad strikes":       NewAdvancedAlertRules(ConsecutiveFailures(5)),
+		"n greater than m":  NewAdvancedAlertRules(LoadTimeAbove(time.Second, 3, 2)),
+		"m above locations": {Locations: []string{"dallas"}, Conditions: []AlertCondition{LoadTimeAbove(time.Second, 1, 2)}},
+		"no step":           NewAdvancedAlertRules(StepLoadTimeAbove(0, time.Second)),
+		"bad ip":            NewAdvancedAlertRules(DNSMismatch("www.example.com", "not-an-ip")),
+		"unknown type":      NewAdvancedAlertRules(AlertCondition{Type: "uptime"}),
+	}
+	for name, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+// TestAdvancedAlertRulesValidateError
+func TestAdvancedAlertRulesValidateError(t *testing.T) {
+	t.Parallel()
+
+	condition := ConsecutiveFailures(5)
+	err := NewAdvancedAlertRules(ConsecutiveFailures(1), condition).Validate()
+	if err == nil || err.Error() != "neustar: condition 2: 5 is not a valid number of strikes" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if inner := errors.Unwrap(err); inner == nil || inner.Error() != condition.Validate(0).Error() {
+		t.Errorf("expected the condition error to be wrapped, got %v", inner)
+	}
+}
@@ -0,0 +1,285 @@
+package neustar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SimulatedAlert holds an alert that would have fired during an evaluation
+type SimulatedAlert struct {
+	// When the alert would have fired
+	Fired time.Time
+
+	// When the alert would have cleared. Zero if it is still open at the
+	// end of the samples.
+	Cleared time.Time
+
+	// The conditions that were met when the alert fired
+	Reasons []string
+
+	// The locations that failed or breached a threshold while the alert
+	// was open
+	Locations []string
+
+	// Set when the alert was only ever caused by a single location while
+	// the others were healthy, which usually points at a transient or
+	// location specific problem rather than an outage
+	FalsePositive bool
+}
+
+// Duration returns how long the alert was open. Alerts that never cleared
+// are measured to the given end of the evaluation.
+func (a SimulatedAlert) Duration(end time.Time) time.Duration {
+	if a.Cleared.IsZero() {
+		return end.Sub(a.Fired)
+	}
+	return a.Cleared.Sub(a.Fired)
+}
+
+// PolicyEvaluation holds the result of replaying samples against a policy
+type PolicyEvaluation struct {
+	// The alerts that would have fired, in time order
+	Alerts []SimulatedAlert
+
+	// The number of samples replayed
+	Samples int
+
+	// The number of samples that did not succeed
+	FailedSamples int
+
+	// The number of alerts flagged as false positives
+	FalsePositives int
+
+	// Conditions that cannot be evaluated from sample data and were skipped
+	Skipped []string
+}
+
+// FalsePositiveRate returns the share of alerts flagged as false positives
+func (e PolicyEvaluation) FalsePositiveRate() float64 {
+	if len(e.Alerts) == 0 {
+		return 0
+	}
+	return float64(e.FalsePositives) / float64(len(e.Alerts))
+}
+
+// String renders a summary of the evaluation with one line per alert
+func (e PolicyEvaluation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d samples, %d failed, %d alerts, %.0f%% false positives\n",
+		e.Samples, e.FailedSamples, len(e.Alerts), e.FalsePositiveRate()*100)
+	for _, alert := range e.Alerts {
+		cleared := "still open"
+		if !alert.Cleared.IsZero() {
+			cleared = "cleared " + alert.Cleared.Format(time.RFC3339)
+		}
+		fmt.Fprintf(&b, "fired %s, %s, %s [%s]", alert.Fired.Format(time.RFC3339), cleared,
+			strings.Join(alert.Reasons, "; "), strings.Join(alert.Locations, ","))
+		if alert.FalsePositive {
+			b.WriteString(" (false positive)")
+		}
+		b.WriteString("\n")
+	}
+	for _, skipped := range e.Skipped {
+		fmt.Fprintf(&b, "skipped %s\n", skipped)
+	}
+	return b.String()
+}
+
+// locationState holds the latest state of a location during an evaluation
+type locationState struct {
+	failures int
+	duration int
+	failed   bool
+}
+
+// EvaluatePolicy replays the given samples against the given policy and
+// reports when alerts would have fired and cleared. Policies without advanced
+// rules fire once a location fails the policy's number of strikes in a row.
+// Load time conditions only count samples that succeeded, so a failure is
+// not also counted as a slow sample; failures are left to consecutive
+// failure conditions. Step load time and DNS conditions need data not
+// included in samples and are skipped, which is an error for policies that
+// match all conditions as the result would not reflect the policy.
+func EvaluatePolicy(policy AlertPolicy, samples []Sample) (PolicyEvaluation, error) {
+	rules := policy.AdvancedRules
+	if rules == nil {
+		if !ValidStrikes(policy.Strikes) {
+			return PolicyEvaluation{}, fmt.Errorf("neustar: %d is not a valid number of strikes", policy.Strikes)
+		}
+		rules = NewAdvancedAlertRules(ConsecutiveFailures(policy.Strikes))
+	}
+	if err := rules.Validate(); err != nil {
+		return PolicyEvaluation{}, err
+	}
+
+	var evaluation PolicyEvaluation
+	var conditions []AlertCondition
+	for _, condition := range rules.Conditions {
+		switch condition.Type {
+		case ConditionLoadTime, ConditionConsecutiveFailures:
+			conditions = append(conditions, condition)
+		default:
+			evaluation.Skipped = append(evaluation.Skipped, condition.Type)
+		}
+	}
+	if len(conditions) == 0 {
+		return evaluation, errors.New("neustar: policy has no conditions that can be evaluated from samples")
+	}
+	if rules.Match == "all" && len(evaluation.Skipped) > 0 {
+		return evaluation, fmt.Errorf("neustar: policy matches all conditions but %s cannot be evaluated from samples",
+			strings.Join(evaluation.Skipped, ", "))
+	}
+
+	included := make(map[string]bool, len(rules.Locations))
+	for _, location := range rules.Locations {
+		included[location] = true
+	}
+
+	type timedSample struct {
+		start time.Time
+		Sample
+	}
+	ordered := make([]timedSample, 0, len(samples))
+	for _, sample := range samples {
+		if len(included) > 0 && !included[sample.Location] {
+			continue
+		}
+		start, err := ParseTimestamp(sample.StartTime)
+		if err != nil {
+			return PolicyEvaluation{}, err
+		}
+		ordered = append(ordered, timedSample{start, sample})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].start.Before(ordered[j].start)
+	})
+
+	states := make(map[string]*locationState)
+	var open *SimulatedAlert
+	var openLocations map[string]bool
+	for _, sample := range ordered {
+		evaluation.Samples++
+		failed := !strings.EqualFold(sample.Status, "SUCCESS")
+		if failed {
+			evaluation.FailedSamples++
+		}
+
+		state, ok := states[sample.Location]
+		if !ok {
+			state = &locationState{}
+			states[sample.Location] = state
+		}
+		state.failed = failed
+		state.duration = sample.Duration
+		if failed {
+			state.failures++
+		} else {
+			state.failures = 0
+		}
+
+		reasons, breaching := evaluateConditions(conditions, rules.Match == "all", states)
+		firing := len(reasons) > 0
+
+		switch {
+		case firing && open == nil:
+			open = &SimulatedAlert{Fired: sample.start, Reasons: reasons}
+			openLocations = make(map[string]bool)
+		case !firing && open != nil:
+			open.Cleared = sample.start
+			evaluation.Alerts = append(evaluation.Alerts, closeAlert(open, openLocations, states))
+			open = nil
+		}
+		if open != nil {
+			for _, location := range breaching {
+				openLocations[location] = true
+			}
+			if failed {
+				openLocations[sample.Location] = true
+			}
+		}
+	}
+	if open != nil {
+		evaluation.Alerts = append(evaluation.Alerts, closeAlert(open, openLocations, states))
+	}
+
+	for _, alert := range evaluation.Alerts {
+		if alert.FalsePositive {
+			evaluation.FalsePositives++
+		}
+	}
+	return evaluation, nil
+}
+
+// evaluateConditions returns the reasons for the conditions that are met and
+// the locations that breached them. No reasons are returned when match all is
+// set and any condition is not met.
+func evaluateConditions(conditions []AlertCondition, matchAll bool, states map[string]*locationState) ([]string, []string) {
+	var reasons, breaching []string
+	for _, condition := range conditions {
+		var locations []string
+		var met bool
+		switch condition.Type {
+		case ConditionConsecutiveFailures:
+			for location, state := range states {
+				if state.failures >= condition.Strikes {
+					locations = append(locations, location)
+				}
+			}
+			met = len(locations) > 0
+		case ConditionLoadTime:
+			for location, state := range states {
+				if !state.failed && state.duration > condition.Threshold {
+					locations = append(locations, location)
+				}
+			}
+			met = len(locations) >= condition.Locations
+		}
+		if !met {
+			if matchAll {
+				return nil, nil
+			}
+			continue
+		}
+		sort.Strings(locations)
+		reasons = append(reasons, describeCondition(condition, locations))
+		breaching = append(breaching, locations...)
+	}
+	return reasons, breaching
+}
+
+// describeCondition explains why the given condition was met
+func describeCondition(condition AlertCondition, locations []string) string {
+	switch condition.Type {
+	case ConditionConsecutiveFailures:
+		return fmt.Sprintf("%d consecutive failures at %s", condition.Strikes, strings.Join(locations, ","))
+	case ConditionLoadTime:
+		return fmt.Sprintf("load time above %dms at %d of %d locations", condition.Threshold, len(locations), condition.OutOf)
+	}
+	return condition.Type
+}
+
+// closeAlert finalises the given alert with the locations seen while it was open
+func closeAlert(alert *SimulatedAlert, locations map[string]bool, states map[string]*locationState) SimulatedAlert {
+	for location := range locations {
+		alert.Locations = append(alert.Locations, location)
+	}
+	sort.Strings(alert.Locations)
+	alert.FalsePositive = len(alert.Locations) == 1 && len(states) > 1
+	return *alert
+}
+
+// EvaluatePolicyForMonitor retrieves the samples of the given monitor for the
+// given period and replays them against the given policy
+func EvaluatePolicyForMonitor(m *Monitoring, monitorID string, policy AlertPolicy, start, end time.Time) (PolicyEvaluation, error) {
+	samples, err := m.allSamples(monitorID, &SampleRequestParameters{
+		StartDate: start.UTC().Format(historyDateFormat),
+		EndDate:   end.UTC().Format(historyDateFormat),
+	})
+	if err != nil {
+		return PolicyEvaluation{}, err
+	}
+	return EvaluatePolicy(policy, samples)
+}
@@ -0,0 +1,139 @@
+package neustar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// evaluationSamples builds one sample per minute per location from the given
+// status strings, where 'x' is a failure and '.' a success
+func evaluationSamples(durations map[string]int, statuses map[string]string) []Sample {
+	start := time.Date(2015, 10, 5, 14, 0, 0, 0, time.UTC)
+	var samples []Sample
+	for location, row := range statuses {
+		for i, c := range row {
+			status := "SUCCESS"
+			if c == 'x' {
+				status = "ERROR"
+			}
+			samples = append(samples, Sample{
+				ID:        fmt.Sprintf("%s-%d", location, i),
+				Status:    status,
+				Location:  location,
+				StartTime: start.Add(time.Duration(i) * time.Minute).Format("2006-01-02T15:04:05"),
+				Duration:  durations[location],
+			})
+		}
+	}
+	return samples
+}
+
+// TestEvaluatePolicy
+func TestEvaluatePolicy(t *testing.T) {
+	t.Parallel()
+
+	samples := evaluationSamples(nil, map[string]string{
+		"dallas": "..xx....xxxx..",
+		"london": "........xxx...",
+	})
+
+	evaluation, err := EvaluatePolicy(AlertPolicy{Strikes: 2}, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evaluation.Samples != 28 || evaluation.FailedSamples != 9 {
+		t.Errorf("expected 28 samples with 9 failures, got %d with %d", evaluation.Samples, evaluation.FailedSamples)
+	}
+	if len(evaluation.Alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got:\n%s", evaluation)
+	}
+
+	first := evaluation.Alerts[0]
+	if first.Fired.Minute() != 3 || first.Cleared.Minute() != 4 || !first.FalsePositive {
+		t.Errorf("expected a false positive alert from 14:03 to 14:04, got %+v", first)
+	}
+	second := evaluation.Alerts[1]
+	if second.Fired.Minute() != 9 || second.Cleared.Minute() != 12 || second.FalsePositive {
+		t.Errorf("expected an alert from 14:09 to 14:12, got %+v", second)
+	}
+	if rate := evaluation.FalsePositiveRate(); rate != 0.5 {
+		t.Errorf("expected a false positive rate of 0.5, got %f", rate)
+	}
+
+	evaluation, err = EvaluatePolicy(AlertPolicy{Strikes: 3}, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluation.Alerts) != 1 {
+		t.Errorf("expected raising strikes to 3 to leave 1 alert, got:\n%s", evaluation)
+	}
+}
+
+// TestEvaluatePolicyAdvanced
+func TestEvaluatePolicyAdvanced(t *testing.T) {
+	t.Parallel()
+
+	samples := evaluationSamples(
+		map[string]int{"dallas": 6000, "london": 6000, "tokyo": 1000},
+		map[string]string{"dallas": "....", "london": "....", "tokyo": "...."},
+	)
+
+	rules := NewAdvancedAlertRules(
+		LoadTimeAbove(5*time.Second, 2, 3),
+		DNSMismatch("www.example.com", "192.0.2.10"),
+	)
+	evaluation, err := EvaluatePolicy(AlertPolicy{AdvancedRules: rules}, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluation.Alerts) != 1 || !evaluation.Alerts[0].Cleared.IsZero() {
+		t.Fatalf("expected a single open alert, got:\n%s", evaluation)
+	}
+	if len(evaluation.Skipped) != 1 || evaluation.Skipped[0] != ConditionDNSMismatch {
+		t.Errorf("expected the DNS condition to be skipped, got %v", evaluation.Skipped)
+	}
+
+	rules.Locations = []string{"tokyo", "dallas"}
+	rules.Conditions = rules.Conditions[:1]
+	rules.Conditions[0].OutOf = 2
+	evaluation, err = EvaluatePolicy(AlertPolicy{AdvancedRules: rules}, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluation.Alerts) != 0 {
+		t.Errorf("expected no alerts when only tokyo and dallas are considered, got:\n%s", evaluation)
+	}
+
+	if _, err := EvaluatePolicy(AlertPolicy{Strikes: 9}, samples); err == nil {
+		t.Error("expected an error for invalid strikes")
+	}
+
+	rules = NewAdvancedAlertRules(
+		LoadTimeAbove(5*time.Second, 2, 3),
+		DNSMismatch("www.example.com", "192.0.2.10"),
+	)
+	rules.Match = "all"
+	evaluation, err = EvaluatePolicy(AlertPolicy{AdvancedRules: rules}, samples)
+	if err == nil || len(evaluation.Skipped) != 1 {
+		t.Errorf("expected match all with a skipped condition to fail, got %v and:\n%s", err, evaluation)
+	}
+}
+
+// TestEvaluatePolicyLoadTimeIgnoresFailures
+func TestEvaluatePolicyLoadTimeIgnoresFailures(t *testing.T) {
+	t.Parallel()
+
+	samples := evaluationSamples(
+		map[string]int{"dallas": 6000, "london": 1000, "tokyo": 1000},
+		map[string]string{"dallas": "....", "london": "xxxx", "tokyo": "...."},
+	)
+	rules := NewAdvancedAlertRules(LoadTimeAbove(5*time.Second, 2, 3))
+	evaluation, err := EvaluatePolicy(AlertPolicy{AdvancedRules: rules}, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluation.Alerts) != 0 {
+		t.Errorf("expected failures not to count as slow samples, got:\n%s", evaluation)
+	}
+}
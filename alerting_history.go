@@ -0,0 +1,233 @@
+package neustar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	// HistoryURI is the endpoint for alert history calls
+	HistoryURI = "/history"
+
+	// AlertMonitorURI is the endpoint for alert calls on a monitor
+	AlertMonitorURI = "/monitor"
+
+	// historyDateFormat is the format dates are sent to the history endpoints in
+	historyDateFormat = "2006-01-02T15:04"
+)
+
+const (
+	// AlertTriggered is the type of event sent when an alert fires
+	AlertTriggered = "triggered"
+
+	// AlertEscalated is the type of event sent when an alert escalates to
+	// the next tier
+	AlertEscalated = "escalated"
+
+	// AlertCleared is the type of event sent when an alert clears
+	AlertCleared = "cleared"
+)
+
+// AlertEvent holds a single entry of the alert history
+type AlertEvent struct {
+	// The ID of the event
+	ID string `json:"id"`
+
+	// The ID of the alert policy that raised the event
+	PolicyID string `json:"policyId"`
+
+	// The name of the alert policy that raised the event
+	PolicyName string `json:"policyName,omitempty"`
+
+	// The ID of the monitor the event is for
+	MonitorID string `json:"monitorId"`
+
+	// The name of the monitor the event is for
+	MonitorName string `json:"monitorName,omitempty"`
+
+	// The type of the event: AlertTriggered, AlertEscalated or AlertCleared
+	Type string `json:"type"`
+
+	// The monitor status when the event was raised, e.g. 'Alerting', 'Warning'
+	// or 'Active'
+	Status string `json:"status,omitempty"`
+
+	// When the event occurred
+	Time Timestamp `json:"time"`
+
+	// The monitoring location the event was raised from
+	Location string `json:"location"`
+
+	// The error that caused the alert
+	Error string `json:"error"`
+}
+
+// AlertHistoryParameters holds the allowed options for retrieving alert history
+type AlertHistoryParameters struct {
+	// An ISO 8601 formatted date string or datetime string representing the
+	// start of the period. Examples: 2012-03-02 or 2012-03-01T12:00
+	StartDate string `url:"startDate"`
+
+	// An ISO 8601 formatted date string or datetime string representing the
+	// end of the period. Examples: 2012-03-02 or 2012-03-01T12:00
+	EndDate string `url:"endDate"`
+
+	// From which position in the return list you wish to start
+	Offset int `url:"offset"`
+}
+
+// AlertHistoryDataResponse holds the return from the API history calls
+type AlertHistoryDataResponse struct {
+	Data struct {
+		Total  int          `json:"total"`
+		Offset int          `json:"offset"`
+		More   bool         `json:"more"`
+		Items  []AlertEvent `json:"items"`
+	} `json:"data"`
+}
+
+// PolicyHistory retrieves a page of the alert events raised by the given
+// policy. If more is true, make another call with the offset set to the
+// number of results returned so far.
+func (a *Alerting) PolicyHistory(policyID string, ahp *AlertHistoryParameters) ([]AlertEvent, bool, error) {
+	return a.history(fmt.Sprintf("%s/%s%s", PolicyURI, policyID, HistoryURI), ahp)
+}
+
+// MonitorHistory retrieves a page of the alert events raised for the given
+// monitor. If more is true, make another call with the offset set to the
+// number of results returned so far.
+func (a *Alerting) MonitorHistory(monitorID string, ahp *AlertHistoryParameters) ([]AlertEvent, bool, error) {
+	return a.history(fmt.Sprintf("%s/%s%s", AlertMonitorURI, monitorID, HistoryURI), ahp)
+}
+
+// history retrieves a page of alert events from the given endpoint
+func (a *Alerting) history(uri string, ahp *AlertHistoryParameters) ([]AlertEvent, bool, error) {
+	if ahp == nil {
+		ahp = &AlertHistoryParameters{}
+	}
+	v, err := query.Values(ahp)
+	if err != nil {
+		return nil, false, err
+	}
+	var response *http.Response
+	var data AlertHistoryDataResponse
+	response, err = http.Get(fmt.Sprintf(
+		"%s%s%s?%s&apikey=%s&sig=%s",
+		a.neustar.baseURL(), AlertURI, uri, v.Encode(), a.neustar.Key, a.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, false, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, false, err
+	}
+	return data.Data.Items, data.Data.More, nil
+}
+
+// TimelineEntry holds a single entry of an incident timeline
+type TimelineEntry struct {
+	// When the entry occurred
+	Time time.Time
+
+	// The alert event type, or the sample status for failed samples
+	Type string
+
+	// The monitoring location of the entry
+	Location string
+
+	// The error message of the alert, or the ID of the failed sample
+	Message string
+
+	// Set for entries built from alert events
+	Event *AlertEvent
+
+	// Set for entries built from failed samples
+	Sample *Sample
+}
+
+// Timeline holds alert events and failed samples in time order
+type Timeline []TimelineEntry
+
+// String renders the timeline with one entry per line
+func (t Timeline) String() string {
+	var b strings.Builder
+	for _, entry := range t {
+		fmt.Fprintf(&b, "%s  %-10s %-14s %s\n",
+			entry.Time.Format(time.RFC3339), entry.Type, entry.Location, entry.Message)
+	}
+	return b.String()
+}
+
+// NewIncidentTimeline merges the given alert events with the failed samples
+// in time order. Samples with a status of SUCCESS are left out.
+func NewIncidentTimeline(events []AlertEvent, samples []Sample) (Timeline, error) {
+	timeline := make(Timeline, 0, len(events)+len(samples))
+	for i := range events {
+		event := &events[i]
+		timeline = append(timeline, TimelineEntry{
+			Time:     event.Time.Time,
+			Type:     event.Type,
+			Location: event.Location,
+			Message:  event.Error,
+			Event:    event,
+		})
+	}
+	for i := range samples {
+		sample := &samples[i]
+		if strings.EqualFold(sample.Status, "SUCCESS") {
+			continue
+		}
+		start, err := ParseTimestamp(sample.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		timeline = append(timeline, TimelineEntry{
+			Time:     start,
+			Type:     strings.ToLower(sample.Status),
+			Location: sample.Location,
+			Message:  fmt.Sprintf("sample %s failed", sample.ID),
+			Sample:   sample,
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+	return timeline, nil
+}
+
+// IncidentTimeline retrieves the alert history and samples of the given
+// monitor for the given period and merges them into a timeline
+func IncidentTimeline(a *Alerting, m *Monitoring, monitorID string, start, end time.Time) (Timeline, error) {
+	ahp := &AlertHistoryParameters{
+		StartDate: start.UTC().Format(historyDateFormat),
+		EndDate:   end.UTC().Format(historyDateFormat),
+	}
+	var events []AlertEvent
+	err := pageAll(func(offset int) (int, bool, error) {
+		ahp.Offset = offset
+		page, more, err := a.MonitorHistory(monitorID, ahp)
+		events = append(events, page...)
+		return len(page), more, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := m.allSamples(monitorID, &SampleRequestParameters{
+		StartDate: ahp.StartDate,
+		EndDate:   ahp.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewIncidentTimeline(events, samples)
+}
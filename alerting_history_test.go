@@ -0,0 +1,103 @@
+package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestNewIncidentTimeline
+func TestNewIncidentTimeline(t *testing.T) {
+	t.Parallel()
+
+	var events []AlertEvent
+	err := json.Unmarshal([]byte(`[
+		{"id": "e1", "type": "triggered", "time": "2015-10-05T14:22:00Z", "location": "dallas", "error": "timeout"},
+		{"id": "e2", "type": "cleared", "time": "2015-10-05T14:40:00Z", "location": "dallas"}
+	]`), &events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []Sample{
+		{ID: "s1", Status: "SUCCESS", Location: "dallas", StartTime: "2015-10-05T14:10:00"},
+		{ID: "s2", Status: "ERROR", Location: "dallas", StartTime: "2015-10-05T14:20:00"},
+		{ID: "s3", Status: "ERROR", Location: "dallas", StartTime: "2015-10-05T14:25:00"},
+	}
+
+	timeline, err := NewIncidentTimeline(events, samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"s2", "e1", "s3", "e2"}
+	if len(timeline) != len(want) {
+		t.Fatalf("expected %d entries, got %d:\n%s", len(want), len(timeline), timeline)
+	}
+	for i, entry := range timeline {
+		var id string
+		if entry.Event != nil {
+			id = entry.Event.ID
+		} else {
+			id = entry.Sample.ID
+		}
+		if id != want[i] {
+			t.Errorf("entry %d: expected %s, got %s", i, want[i], id)
+		}
+	}
+
+	if _, err := NewIncidentTimeline(nil, []Sample{{Status: "ERROR", StartTime: "yesterday"}}); err == nil {
+		t.Error("expected an error for an unparseable sample start time")
+	}
+}
+
+// TestMonitorHistoryError
+func TestMonitorHistoryError(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "MON_0011", "message": "User does not have permission to perform the action"}}`))
+	})
+	events, more, err := NewAlerting(n).MonitorHistory("m1", nil)
+	if !errors.Is(err, &ResponseError{StatusCode: http.StatusForbidden}) || events != nil || more {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+}
+
+// TestIncidentTimelinePagesSamples
+func TestIncidentTimelinePagesSamples(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/alert/1.0/monitor/m1/history":
+			w.Write([]byte(`{"data": {"more": false, "items": []}}`))
+		case "/monitor/1.0/m1/sample":
+			var data SamplesDataResponse
+			switch r.URL.Query().Get("offset") {
+			case "0":
+				data.Data.Items = make([]Sample, maxSamplesPerCall)
+				for i := range data.Data.Items {
+					data.Data.Items[i] = Sample{Status: "SUCCESS", StartTime: "2015-10-05T14:00:00"}
+				}
+			case "2000":
+				data.Data.Items = []Sample{{ID: "s1", Status: "ERROR", StartTime: "2015-10-05T14:20:00"}}
+			default:
+				t.Errorf("unexpected sample offset %s", r.URL.Query().Get("offset"))
+			}
+			json.NewEncoder(w).Encode(data)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+	start := time.Date(2015, 10, 5, 14, 0, 0, 0, time.UTC)
+	timeline, err := IncidentTimeline(NewAlerting(n), NewMonitor(n), "m1", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeline) != 1 || timeline[0].Sample.ID != "s1" {
+		t.Errorf("expected the failed sample from the second page, got:\n%s", timeline)
+	}
+}
@@ -0,0 +1,362 @@
+package neustar
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultNotificationTemplate is the message template used by notifiers
+// without a template of their own
+var DefaultNotificationTemplate = template.Must(template.New("notification").Parse(
+	`[{{.Title}}] {{.MonitorName}}{{with .Event.Location}} at {{.}}{{end}}{{with .LastError}}: {{.}}{{end}}`))
+
+// DefaultSubjectTemplate is the subject template used by SMTPNotifier
+var DefaultSubjectTemplate = template.Must(template.New("subject").Parse(
+	`[{{.Title}}] {{.MonitorName}}`))
+
+// DefaultNotifyTimeout bounds how long NotifyHandler waits for a
+// notification and is the timeout of the default notifier HTTP client and of
+// SMTP deliveries without a deadline
+const DefaultNotifyTimeout = 30 * time.Second
+
+// notifyClient is the client used by notifiers without one of their own
+var notifyClient = &http.Client{Timeout: DefaultNotifyTimeout}
+
+// Notifier sends a notification about an alert event somewhere
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Notification holds an alert event and the summary of its monitor, which
+// is passed to the message templates
+type Notification struct {
+	Event AlertEvent
+
+	// The summary of the monitor, if one could be retrieved
+	Summary *SummaryDataResponse
+
+	// Why the summary could not be retrieved, if it was requested
+	SummaryErr error
+}
+
+// NewNotification creates a notification for the given event including the
+// summary of its monitor. A nil Monitoring skips the summary. If the summary
+// cannot be retrieved the notification is still returned, without it, along
+// with the error.
+func NewNotification(m *Monitoring, event AlertEvent) (Notification, error) {
+	n := Notification{Event: event}
+	if m == nil {
+		return n, nil
+	}
+	summaries, err := m.Summary(event.MonitorID)
+	if err != nil {
+		n.SummaryErr = fmt.Errorf("neustar: summary of monitor %s: %w", event.MonitorID, err)
+		return n, n.SummaryErr
+	}
+	if len(summaries) > 0 {
+		n.Summary = &summaries[0]
+	}
+	return n, nil
+}
+
+// Title returns the event type or status in upper case
+func (n Notification) Title() string {
+	if n.Event.Type != "" {
+		return strings.ToUpper(n.Event.Type)
+	}
+	return strings.ToUpper(n.Event.Status)
+}
+
+// MonitorName returns the name of the monitor, falling back to its ID
+func (n Notification) MonitorName() string {
+	if n.Event.MonitorName != "" {
+		return n.Event.MonitorName
+	}
+	return n.Event.MonitorID
+}
+
+// LastError returns the error of the event, falling back to the last error
+// of the monitor summary
+func (n Notification) LastError() string {
+	if n.Event.Error != "" {
+		return n.Event.Error
+	}
+	if n.Summary != nil {
+		return n.Summary.LastErrorMessage
+	}
+	return ""
+}
+
+// Render executes the given template with the notification. A nil template
+// uses DefaultNotificationTemplate.
+func (n Notification) Render(t *template.Template) (string, error) {
+	if t == nil {
+		t = DefaultNotificationTemplate
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, n); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// MultiNotifier sends every notification to all of its notifiers
+type MultiNotifier []Notifier
+
+// Notify sends the notification to every notifier and returns their errors
+// combined
+func (m MultiNotifier) Notify(ctx context.Context, n Notification) error {
+	var failures []string
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// NotifyHandler returns an AlertEventHandler that sends every event received
+// by an AlertWebhook to the given notifier, including the summary of the
+// monitor when m is not nil. A failed summary does not hold the alert back;
+// the notification is sent without it and carries the error in SummaryErr.
+// Sending is cancelled after DefaultNotifyTimeout so a hung endpoint cannot
+// block the webhook.
+func NotifyHandler(m *Monitoring, notifier Notifier) AlertEventHandler {
+	return func(event AlertEvent) error {
+		n, _ := NewNotification(m, event)
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultNotifyTimeout)
+		defer cancel()
+		return notifier.Notify(ctx, n)
+	}
+}
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+
+	// The message template. Defaults to DefaultNotificationTemplate.
+	Template *template.Template
+
+	// The client used to post. Defaults to a client with a
+	// DefaultNotifyTimeout timeout.
+	Client *http.Client
+}
+
+// NewSlackNotifier creates a new SlackNotifier object
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL: webhookURL,
+	}
+}
+
+// Notify posts the rendered message to the Slack webhook
+func (s *SlackNotifier) Notify(ctx context.Context, n Notification) error {
+	message, err := n.Render(s.Template)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, nil, map[string]string{"text": message})
+}
+
+// WebhookNotifier posts notifications as JSON to a generic webhook
+type WebhookNotifier struct {
+	URL string
+
+	// Headers added to every request, e.g. for authentication
+	Headers map[string]string
+
+	// The message template. Defaults to DefaultNotificationTemplate.
+	Template *template.Template
+
+	// The client used to post. Defaults to a client with a
+	// DefaultNotifyTimeout timeout.
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier object
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:     url,
+		Headers: make(map[string]string),
+	}
+}
+
+// WebhookPayload holds the body posted by WebhookNotifier
+type WebhookPayload struct {
+	Message string               `json:"message"`
+	Event   AlertEvent           `json:"event"`
+	Summary *SummaryDataResponse `json:"summary,omitempty"`
+}
+
+// Notify posts the event, monitor summary and rendered message to the webhook
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	message, err := n.Render(w.Template)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.Client, w.URL, w.Headers, WebhookPayload{
+		Message: message,
+		Event:   n.Event,
+		Summary: n.Summary,
+	})
+}
+
+// postJSON posts the given payload and fails on a non 2xx response
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	buffer, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(buffer))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	if client == nil {
+		client = notifyClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("neustar: posting notification to %s returned %s", url, response.Status)
+	}
+	return nil
+}
+
+// SMTPNotifier sends notifications by email
+type SMTPNotifier struct {
+	// The host:port of the SMTP server
+	Addr string
+
+	From string
+	To   []string
+
+	// Optional authentication. STARTTLS is used when the server offers it.
+	Auth smtp.Auth
+
+	// The subject template. Defaults to DefaultSubjectTemplate.
+	Subject *template.Template
+
+	// The body template. Defaults to DefaultNotificationTemplate.
+	Template *template.Template
+}
+
+// NewSMTPNotifier creates a new SMTPNotifier object
+func NewSMTPNotifier(addr, from string, to ...string) *SMTPNotifier {
+	return &SMTPNotifier{
+		Addr: addr,
+		From: from,
+		To:   to,
+	}
+}
+
+// Notify sends the rendered message to every recipient
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	if len(s.To) == 0 {
+		return errors.New("neustar: SMTP notifier has no recipients")
+	}
+	for _, address := range append([]string{s.From}, s.To...) {
+		if strings.ContainsAny(address, "\r\n") {
+			return fmt.Errorf("neustar: SMTP address %q contains a line break", address)
+		}
+	}
+	subjectTemplate := s.Subject
+	if subjectTemplate == nil {
+		subjectTemplate = DefaultSubjectTemplate
+	}
+	subject, err := n.Render(subjectTemplate)
+	if err != nil {
+		return err
+	}
+	body, err := n.Render(s.Template)
+	if err != nil {
+		return err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", s.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	message.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+	message.WriteString("\r\n")
+
+	return s.send(ctx, message.Bytes())
+}
+
+// send delivers the message, honouring the deadline of the context or
+// DefaultNotifyTimeout if it has none
+func (s *SMTPNotifier) send(ctx context.Context, message []byte) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultNotifyTimeout)
+		defer cancel()
+	}
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if err := client.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,202 @@
+package neustar
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testNotification is the notification sent by the notifier tests
+var testNotification = Notification{
+	Event: AlertEvent{
+		MonitorID:   "m1",
+		MonitorName: "checkout",
+		Type:        AlertTriggered,
+		Location:    "dallas",
+	},
+	Summary: &SummaryDataResponse{LastErrorMessage: "element #buy not found"},
+}
+
+// TestNotificationRender
+func TestNotificationRender(t *testing.T) {
+	t.Parallel()
+
+	message, err := testNotification.Render(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[TRIGGERED] checkout at dallas: element #buy not found"; message != want {
+		t.Errorf("expected %q, got %q", want, message)
+	}
+}
+
+// TestSlackAndWebhookNotifiers
+func TestSlackAndWebhookNotifiers(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan map[string]interface{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "nope", http.StatusBadGateway)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		body["auth"] = r.Header.Get("Authorization")
+		bodies <- body
+	}))
+	defer server.Close()
+
+	webhook := NewWebhookNotifier(server.URL + "/incident")
+	webhook.Headers["Authorization"] = "Bearer token"
+	notifier := MultiNotifier{NewSlackNotifier(server.URL + "/slack"), webhook}
+	if err := notifier.Notify(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+
+	slack := <-bodies
+	if slack["text"] != "[TRIGGERED] checkout at dallas: element #buy not found" {
+		t.Errorf("unexpected slack body %v", slack)
+	}
+	incident := <-bodies
+	event, _ := incident["event"].(map[string]interface{})
+	if incident["auth"] != "Bearer token" || event["monitorId"] != "m1" || incident["summary"] == nil {
+		t.Errorf("unexpected webhook body %v", incident)
+	}
+
+	if err := NewSlackNotifier(server.URL+"/fail").Notify(context.Background(), testNotification); err == nil {
+		t.Error("expected an error for a failed post")
+	}
+}
+
+// TestSMTPNotifier
+func TestSMTPNotifier(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	notifier := NewSMTPNotifier(listener.Addr().String(), "neustar@example.com", "oncall@example.com")
+	if err := notifier.Notify(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+
+	message := <-received
+	for _, want := range []string{"To: oncall@example.com", "Subject: [TRIGGERED] checkout", "checkout at dallas: element #buy not found"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, message)
+		}
+	}
+}
+
+// TestSMTPNotifierHeaderInjection
+func TestSMTPNotifierHeaderInjection(t *testing.T) {
+	t.Parallel()
+
+	for _, notifier := range []*SMTPNotifier{
+		NewSMTPNotifier("127.0.0.1:0", "neustar@example.com\r\nBcc: all@example.com", "oncall@example.com"),
+		NewSMTPNotifier("127.0.0.1:0", "neustar@example.com", "oncall@example.com\nBcc: all@example.com"),
+	} {
+		err := notifier.Notify(context.Background(), testNotification)
+		if err == nil || !strings.Contains(err.Error(), "line break") {
+			t.Errorf("expected a line break error, got %v", err)
+		}
+	}
+}
+
+// TestNotifyClientTimeout
+func TestNotifyClientTimeout(t *testing.T) {
+	t.Parallel()
+
+	if notifyClient.Timeout != DefaultNotifyTimeout {
+		t.Errorf("expected the default notifier client to time out after %s, got %s", DefaultNotifyTimeout, notifyClient.Timeout)
+	}
+}
+
+// notifierFunc adapts a function to the Notifier interface
+type notifierFunc func(context.Context, Notification) error
+
+// Notify calls f
+func (f notifierFunc) Notify(ctx context.Context, n Notification) error {
+	return f(ctx, n)
+}
+
+// TestNotifyHandlerSummaryError
+func TestNotifyHandlerSummaryError(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	var sent []Notification
+	handler := NotifyHandler(NewMonitor(n), notifierFunc(func(ctx context.Context, n Notification) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected the notification to have a deadline")
+		}
+		sent = append(sent, n)
+		return nil
+	}))
+	if err := handler(testNotification.Event); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("expected the alert to be sent once, got %d", len(sent))
+	}
+	var re *ResponseError
+	if sent[0].Summary != nil || !errors.As(sent[0].SummaryErr, &re) || re.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected no summary and a wrapped 503, got %+v", sent[0])
+	}
+}
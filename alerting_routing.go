@@ -0,0 +1,139 @@
+package neustar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// smsPattern matches phone numbers in E.164 format, e.g. +15555550100
+var smsPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// EscalationTier holds the recipients notified once an alert has been open
+// for the given delay
+type EscalationTier struct {
+	// Minutes after the alert is triggered before this tier is notified
+	Delay int `json:"delay"`
+
+	// Email addresses notified by this tier
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	// Phone numbers in E.164 format sent an SMS by this tier
+	SMS []string `json:"sms,omitempty"`
+}
+
+// EscalationPolicy holds the tiers an open alert escalates through
+type EscalationPolicy struct {
+	Tiers []EscalationTier `json:"tiers"`
+}
+
+// NewEscalationPolicy creates a new EscalationPolicy object
+func NewEscalationPolicy() *EscalationPolicy {
+	return &EscalationPolicy{}
+}
+
+// AddTier adds a tier that notifies the given email addresses and phone
+// numbers once an alert has been open for the given duration
+func (e *EscalationPolicy) AddTier(after time.Duration, emailAddresses, sms []string) *EscalationPolicy {
+	e.Tiers = append(e.Tiers, EscalationTier{
+		Delay:          int(after / time.Minute),
+		EmailAddresses: emailAddresses,
+		SMS:            sms,
+	})
+	return e
+}
+
+// Validate makes sure every tier has a recipient and the tiers are ordered
+// by increasing delay
+func (e *EscalationPolicy) Validate() error {
+	if len(e.Tiers) == 0 {
+		return errors.New("neustar: escalation policy has no tiers")
+	}
+	for i, tier := range e.Tiers {
+		if len(tier.EmailAddresses) == 0 && len(tier.SMS) == 0 {
+			return fmt.Errorf("neustar: escalation tier %d has no recipients", i+1)
+		}
+		if i > 0 && tier.Delay <= e.Tiers[i-1].Delay {
+			return fmt.Errorf("neustar: escalation tier %d must have a longer delay than tier %d", i+1, i)
+		}
+		for _, number := range tier.SMS {
+			if !ValidSMS(number) {
+				return fmt.Errorf("neustar: %s is not a valid SMS number", number)
+			}
+		}
+	}
+	return nil
+}
+
+// AlertRouting holds where the alerts of a policy are sent beyond its email
+// addresses. It is embedded in the alert policy parameters and responses.
+type AlertRouting struct {
+	// Tiers an open alert escalates through
+	EscalationPolicy *EscalationPolicy `json:"escalationPolicy,omitempty"`
+
+	// Phone numbers in E.164 format sent an SMS when an alert is triggered
+	SMS []string `json:"sms,omitempty"`
+
+	// The PagerDuty service key alerts are sent to
+	PagerDutyServiceKey string `json:"pagerDutyServiceKey,omitempty"`
+
+	// The PagerDuty account the service belongs to
+	PagerDutyAccount string `json:"pagerDutyAccount,omitempty"`
+
+	// The name of the PagerDuty connection in the web portal
+	PagerDutyConnectionName string `json:"pagerDutyConnectionName,omitempty"`
+
+	// The IDs of the feeds alerts are published to
+	FeedIDs []string `json:"feedIds,omitempty"`
+}
+
+// WithEscalation sets the escalation policy
+func (r *AlertRouting) WithEscalation(e *EscalationPolicy) *AlertRouting {
+	r.EscalationPolicy = e
+	return r
+}
+
+// WithSMS adds the given phone numbers to the SMS recipients
+func (r *AlertRouting) WithSMS(numbers ...string) *AlertRouting {
+	r.SMS = append(r.SMS, numbers...)
+	return r
+}
+
+// WithPagerDuty sends alerts to the given PagerDuty service
+func (r *AlertRouting) WithPagerDuty(account, serviceKey, connectionName string) *AlertRouting {
+	r.PagerDutyAccount = account
+	r.PagerDutyServiceKey = serviceKey
+	r.PagerDutyConnectionName = connectionName
+	return r
+}
+
+// WithFeeds adds the given feed IDs
+func (r *AlertRouting) WithFeeds(ids ...string) *AlertRouting {
+	r.FeedIDs = append(r.FeedIDs, ids...)
+	return r
+}
+
+// Validate makes sure the SMS numbers, escalation tiers and PagerDuty
+// settings are valid
+func (r *AlertRouting) Validate() error {
+	for _, number := range r.SMS {
+		if !ValidSMS(number) {
+			return fmt.Errorf("neustar: %s is not a valid SMS number", number)
+		}
+	}
+	if r.EscalationPolicy != nil {
+		if err := r.EscalationPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+	if (r.PagerDutyAccount != "" || r.PagerDutyConnectionName != "") && r.PagerDutyServiceKey == "" {
+		return errors.New("neustar: PagerDuty service key is required")
+	}
+	return nil
+}
+
+// ValidSMS verifies the given phone number is in E.164 format
+func ValidSMS(number string) bool {
+	return smsPattern.MatchString(number)
+}
@@ -1 +1,173 @@
 package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewAlerting
+func TestNewAlerting(t *testing.T) {
+	t.Parallel()
+
+	a := NewAlerting(setUp())
+
+	if reflect.TypeOf(a).String() != "*neustar.Alerting" {
+		t.Error("Incorrect data type pointer returned from NewAlerting function")
+	}
+}
+
+// TestValidStrikes
+func TestValidStrikes(t *testing.T) {
+	t.Parallel()
+
+	for _, strike := range Strikes {
+		if !ValidStrikes(strike) {
+			t.Errorf("expected %d to be a valid strike", strike)
+		}
+	}
+	if ValidStrikes(4) {
+		t.Error("expected 4 to be an invalid strike")
+	}
+}
+
+// TestAlertRouting
+func TestAlertRouting(t *testing.T) {
+	t.Parallel()
+
+	napp := &NewAlertPolicyParameters{Name: "checkout", Strikes: 2}
+	napp.WithSMS("+15555550100").
+		WithPagerDuty("acme", "0123456789abcdef0123456789abcdef", "ops").
+		WithEscalation(NewEscalationPolicy().
+			AddTier(0, []string{"oncall@example.com"}, nil).
+			AddTier(15*time.Minute, nil, []string{"+15555550101"}))
+
+	if err := napp.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(napp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"sms":["+15555550100"]`, `"pagerDutyServiceKey":"0123456789abcdef0123456789abcdef"`, `"tiers":[{"delay":0,`, `{"delay":15,"sms":["+15555550101"]}`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("expected %s to contain %s", b, want)
+		}
+	}
+
+	invalid := []*AlertRouting{
+		new(AlertRouting).WithSMS("555-0100"),
+		new(AlertRouting).WithPagerDuty("acme", "", ""),
+		new(AlertRouting).WithEscalation(NewEscalationPolicy()),
+		new(AlertRouting).WithEscalation(NewEscalationPolicy().AddTier(0, nil, nil)),
+		new(AlertRouting).WithEscalation(NewEscalationPolicy().
+			AddTier(10*time.Minute, []string{"a@example.com"}, nil).
+			AddTier(5*time.Minute, []string{"b@example.com"}, nil)),
+	}
+	for i, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+// TestAlertingErrorResponses
+func TestAlertingErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": "MON_0004", "message": "policy not found"}}`))
+	})
+	a := NewAlerting(n)
+
+	calls := map[string]func() error{
+		"NewAlertPolicy": func() error {
+			_, err := a.NewAlertPolicy(&NewAlertPolicyParameters{Name: "checkout", Strikes: 2})
+			return err
+		},
+		"ListAlertPolicies": func() error {
+			_, err := a.ListAlertPolicies()
+			return err
+		},
+		"GetAlertPolicy": func() error {
+			_, err := a.GetAlertPolicy("p1")
+			return err
+		},
+		"UpdateAlertPolicy": func() error {
+			_, err := a.UpdateAlertPolicy("p1", &UpdateAlertPolicyParameters{Strikes: 3})
+			return err
+		},
+	}
+	for name, call := range calls {
+		var re *ResponseError
+		if err := call(); !errors.As(err, &re) || re.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected a 404 ResponseError, got %v", name, err)
+		}
+	}
+
+	status, err := a.DeleteAlertPolicy("p1")
+	if status != http.StatusNotFound || err == nil {
+		t.Errorf("DeleteAlertPolicy: expected 404 and an error, got %d and %v", status, err)
+	}
+}
+
+// TestListAlertPolicies
+func TestListAlertPolicies(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`{"data": {"more": true, "items": [{"id": "p1"}, {"id": "p2"}]}}`))
+		case "2":
+			w.Write([]byte(`{"data": {"more": false, "items": [{"id": "p3"}]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+	policies, err := NewAlerting(n).ListAlertPolicies()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 3 || policies[2].ID != "p3" {
+		t.Errorf("expected the policies of both pages, got %+v", policies)
+	}
+}
+
+// TestAlertPolicyStrikes
+func TestAlertPolicyStrikes(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"items": {"id": "p1"}}}`))
+	})
+	a := NewAlerting(n)
+	if _, err := a.NewAlertPolicy(&NewAlertPolicyParameters{Name: "checkout"}); err == nil {
+		t.Error("NewAlertPolicy: expected an error for missing strikes")
+	}
+	if _, err := a.UpdateAlertPolicy("p1", &UpdateAlertPolicyParameters{Strikes: 4}); err == nil {
+		t.Error("UpdateAlertPolicy: expected an error for invalid strikes")
+	}
+	if _, err := a.UpdateAlertPolicy("p1", &UpdateAlertPolicyParameters{Name: "renamed"}); err != nil {
+		t.Errorf("UpdateAlertPolicy: expected unset strikes to be left alone, got %v", err)
+	}
+}
+
+// TestAlertPolicyNilParameters
+func TestAlertPolicyNilParameters(t *testing.T) {
+	t.Parallel()
+
+	a := NewAlerting(setUp())
+	if _, err := a.NewAlertPolicy(nil); err == nil {
+		t.Error("NewAlertPolicy: expected an error for nil parameters")
+	}
+	if _, err := a.UpdateAlertPolicy("p1", nil); err == nil {
+		t.Error("UpdateAlertPolicy: expected an error for nil parameters")
+	}
+}
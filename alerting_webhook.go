@@ -0,0 +1,186 @@
+package neustar
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// WebhookSignatureHeader is the header holding the HMAC-SHA256 of the
+	// webhook body, hex encoded and prefixed with 'sha256='
+	WebhookSignatureHeader = "X-Neustar-Signature"
+
+	// WebhookSecretParameter is the query parameter holding the shared
+	// secret when the webhook URL carries it instead of a signature
+	WebhookSecretParameter = "secret"
+
+	// DefaultWebhookMaxBodySize is the largest webhook body accepted
+	DefaultWebhookMaxBodySize = 1 << 20
+)
+
+// AlertEventHandler is called with every event received by an AlertWebhook
+type AlertEventHandler func(AlertEvent) error
+
+// webhookEvent holds an alert event as posted to a webhook, which names the
+// error field differently to the history API
+type webhookEvent struct {
+	AlertEvent
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// AlertWebhook is an http.Handler that receives Neustar alert webhooks,
+// decodes them into AlertEvents and fans them out to the registered handlers
+type AlertWebhook struct {
+	// The shared secret requests must be signed with or carry. Empty
+	// disables verification.
+	Secret string
+
+	// The largest body accepted. Defaults to DefaultWebhookMaxBodySize.
+	MaxBodySize int64
+
+	// Called with every event a handler fails on. The delivery is still
+	// acknowledged, so Neustar does not retry it and re-run the handlers
+	// that succeeded.
+	OnError func(AlertEvent, error)
+
+	mu       sync.RWMutex
+	handlers []AlertEventHandler
+}
+
+// NewAlertWebhook creates a new AlertWebhook object verifying requests with
+// the given shared secret
+func NewAlertWebhook(secret string) *AlertWebhook {
+	return &AlertWebhook{
+		Secret:      secret,
+		MaxBodySize: DefaultWebhookMaxBodySize,
+	}
+}
+
+// Handle registers a handler called with every received event. Handlers are
+// called in the order they were registered.
+func (w *AlertWebhook) Handle(handler AlertEventHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// ServeHTTP verifies and decodes the webhook request and passes every event
+// to the registered handlers. A body may hold a single event or a list of
+// events. The request is acknowledged once every handler has run; handler
+// errors are passed to OnError.
+func (w *AlertWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := w.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultWebhookMaxBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > limit {
+		http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !w.verify(r, body) {
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	events, err := DecodeAlertWebhook(body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.mu.RLock()
+	handlers := make([]AlertEventHandler, len(w.handlers))
+	copy(handlers, w.handlers)
+	w.mu.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			if err := handler(event); err != nil && w.OnError != nil {
+				w.OnError(event, err)
+			}
+		}
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the request carries a valid signature or the shared secret
+func (w *AlertWebhook) verify(r *http.Request, body []byte) bool {
+	if w.Secret == "" {
+		return true
+	}
+	if signature := r.Header.Get(WebhookSignatureHeader); signature != "" {
+		given, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+		if err != nil {
+			return false
+		}
+		return hmac.Equal(given, SignWebhook(w.Secret, body))
+	}
+	secret := r.URL.Query().Get(WebhookSecretParameter)
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(w.Secret)) == 1
+}
+
+// SignWebhook returns the HMAC-SHA256 of the given body with the given secret
+func SignWebhook(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// DecodeAlertWebhook decodes a webhook body holding a single event or a list
+// of events and makes sure each one names a monitor and a type or status
+func DecodeAlertWebhook(body []byte) ([]AlertEvent, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("neustar: empty webhook body")
+	}
+
+	var received []webhookEvent
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &received); err != nil {
+			return nil, err
+		}
+	} else {
+		var event webhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+		received = append(received, event)
+	}
+
+	events := make([]AlertEvent, 0, len(received))
+	for i, event := range received {
+		if event.MonitorID == "" {
+			return nil, fmt.Errorf("neustar: webhook event %d has no monitorId", i)
+		}
+		if event.Type == "" && event.Status == "" {
+			return nil, fmt.Errorf("neustar: webhook event %d has no type or status", i)
+		}
+		if event.Error == "" {
+			event.Error = event.ErrorMessage
+		}
+		events = append(events, event.AlertEvent)
+	}
+	return events, nil
+}
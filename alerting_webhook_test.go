@@ -0,0 +1,85 @@
+package neustar
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAlertWebhook
+func TestAlertWebhook(t *testing.T) {
+	t.Parallel()
+
+	webhook := NewAlertWebhook("s3cret")
+	var received []AlertEvent
+	webhook.Handle(func(event AlertEvent) error {
+		received = append(received, event)
+		return nil
+	})
+
+	body := `{"monitorId": "m1", "monitorName": "checkout", "type": "triggered", "status": "Alerting",
+		"location": "dallas", "errorMessage": "timeout", "time": "2015-10-05T14:22:00Z"}`
+	signature := "sha256=" + hex.EncodeToString(SignWebhook("s3cret", []byte(body)))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		sig    string
+		body   string
+		want   int
+	}{
+		{"signed", "POST", "/", signature, body, http.StatusNoContent},
+		{"secret parameter", "POST", "/?secret=s3cret", "", body, http.StatusNoContent},
+		{"bad signature", "POST", "/", "sha256=00", body, http.StatusUnauthorized},
+		{"missing secret", "POST", "/", "", body, http.StatusUnauthorized},
+		{"wrong method", "GET", "/?secret=s3cret", "", "", http.StatusMethodNotAllowed},
+		{"missing monitor", "POST", "/?secret=s3cret", "", `{"type": "triggered"}`, http.StatusBadRequest},
+		{"list", "POST", "/?secret=s3cret", "", `[{"monitorId": "m2", "status": "Active"}]`, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if tt.sig != "" {
+			r.Header.Set(WebhookSignatureHeader, tt.sig)
+		}
+		w := httptest.NewRecorder()
+		webhook.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.want, w.Code, w.Body)
+		}
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(received))
+	}
+	if e := received[0]; e.MonitorID != "m1" || e.Location != "dallas" || e.Error != "timeout" || e.Status != "Alerting" || e.Time.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if received[2].MonitorID != "m2" {
+		t.Errorf("expected the event from the list body, got %+v", received[2])
+	}
+
+	var failed []error
+	webhook.OnError = func(event AlertEvent, err error) {
+		if event.MonitorID != "m1" {
+			t.Errorf("unexpected failed event %+v", event)
+		}
+		failed = append(failed, err)
+	}
+	webhook.Handle(func(AlertEvent) error { return errors.New("pager down") })
+	w := httptest.NewRecorder()
+	webhook.ServeHTTP(w, httptest.NewRequest("POST", "/?secret=s3cret", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected a handler error to still acknowledge the delivery, got %d", w.Code)
+	}
+	if len(failed) != 1 || failed[0].Error() != "pager down" {
+		t.Errorf("expected the handler error to be reported, got %v", failed)
+	}
+	if len(received) != 4 {
+		t.Errorf("expected the other handler to run once, got %d events", len(received))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultProfile is the profile used when none is given
+const defaultProfile = "default"
+
+// credentialsError is returned when no usable credentials are found
+type credentialsError struct {
+	message string
+}
+
+func (e *credentialsError) Error() string {
+	return e.message
+}
+
+// resolveCredentials returns the API key and secret. An explicitly given
+// profile wins, then NEUSTAR_KEY and NEUSTAR_SECRET, then the profile named by
+// NEUSTAR_PROFILE or the default profile. Profiles are read from the file
+// named by NEUSTAR_CREDENTIALS, or ~/.neustar/credentials.
+func resolveCredentials(profile string, getenv func(string) string) (string, string, error) {
+	if profile == "" {
+		key, secret := getenv("NEUSTAR_KEY"), getenv("NEUSTAR_SECRET")
+		if key != "" && secret != "" {
+			return key, secret, nil
+		}
+		if profile = getenv("NEUSTAR_PROFILE"); profile == "" {
+			profile = defaultProfile
+		}
+	}
+
+	path := getenv("NEUSTAR_CREDENTIALS")
+	if path == "" {
+		home := getenv("HOME")
+		if home == "" {
+			return "", "", &credentialsError{"no credentials: set NEUSTAR_KEY and NEUSTAR_SECRET"}
+		}
+		path = filepath.Join(home, ".neustar", "credentials")
+	}
+	profiles, err := readProfiles(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", &credentialsError{"no credentials: set NEUSTAR_KEY and NEUSTAR_SECRET or create " + path}
+		}
+		return "", "", err
+	}
+	p, ok := profiles[profile]
+	if !ok {
+		return "", "", &credentialsError{fmt.Sprintf("profile %q not found in %s", profile, path)}
+	}
+	if p["key"] == "" || p["secret"] == "" {
+		return "", "", &credentialsError{fmt.Sprintf("profile %q in %s requires a key and secret", profile, path)}
+	}
+	return p["key"], p["secret"], nil
+}
+
+// readProfiles reads the profiles of the given INI style credentials file
+func readProfiles(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles := make(map[string]map[string]string)
+	var section map[string]string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			section = make(map[string]string)
+			profiles[name] = section
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 || section == nil {
+			return nil, fmt.Errorf("%s:%d: expected a [profile] or key = value", path, n)
+		}
+		section[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return profiles, scanner.Err()
+}
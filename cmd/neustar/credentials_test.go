@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolveCredentials
+func TestResolveCredentials(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	contents := "# neustar credentials\n[default]\nkey = dkey\nsecret = dsecret\n\n[oncall]\nkey=okey\nsecret = osecret\n\n[broken]\nkey = bkey\n"
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile string
+		vars    map[string]string
+		key     string
+		secret  string
+	}{
+		{"", map[string]string{"NEUSTAR_KEY": "ekey", "NEUSTAR_SECRET": "esecret"}, "ekey", "esecret"},
+		{"oncall", map[string]string{"NEUSTAR_KEY": "ekey", "NEUSTAR_SECRET": "esecret"}, "okey", "osecret"},
+		{"", map[string]string{"NEUSTAR_KEY": "ekey"}, "dkey", "dsecret"},
+		{"", map[string]string{"NEUSTAR_PROFILE": "oncall"}, "okey", "osecret"},
+	}
+	for _, test := range tests {
+		test.vars["NEUSTAR_CREDENTIALS"] = path
+		key, secret, err := resolveCredentials(test.profile, env(test.vars))
+		if err != nil {
+			t.Errorf("%q %v: %s", test.profile, test.vars, err)
+			continue
+		}
+		if key != test.key || secret != test.secret {
+			t.Errorf("%q %v: expected %s/%s, got %s/%s", test.profile, test.vars, test.key, test.secret, key, secret)
+		}
+	}
+
+	for _, profile := range []string{"missing", "broken"} {
+		_, _, err := resolveCredentials(profile, env(map[string]string{"NEUSTAR_CREDENTIALS": path}))
+		if _, ok := err.(*credentialsError); !ok {
+			t.Errorf("%s: expected a credentials error, got %v", profile, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Command neustar is a command line client for the Neustar Web Performance
+// Management API.
+//
+// Usage:
+//
+//	neustar [-profile name] [-o table|json|yaml] <command> <subcommand> [arguments]
+//
+// neustar watch keeps a refreshing status table of all, or all tagged,
+// monitors on screen until interrupted. With -once it renders the table a
+// single time, which is the only mode that honors -o json and -o yaml.
+//
+// Credentials are read from the NEUSTAR_KEY and NEUSTAR_SECRET environment
+// variables or from a profile in ~/.neustar/credentials:
+//
+//	[default]
+//	key = abc123
+//	secret = s3cret
+//
+// The exit code is 0 on success, 1 on unexpected failures, 2 on usage errors,
+// 3 when the credentials are missing or rejected, 4 when a resource is not
+// found, 5 when the API rejects the request and 6 when the API fails.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/briandowns/neustar"
+)
+
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitCredentials = 3
+	exitNotFound    = 4
+	exitAPIClient   = 5
+	exitAPIServer   = 6
+)
+
+const usage = `usage: neustar [-profile name] [-o table|json|yaml] <command> [arguments]
+
+commands:
+  monitors list
+  monitors get <id>
+  monitors create -name <name> -script <id> -locations <a,b> [flags]
+  monitors update [flags] <id>
+  monitors delete <id>
+  monitors summary <id>
+  monitors samples [-start date] [-end date] <id>
+  watch [-interval 30s] [-tag a,b] [-once] [-no-color]
+`
+
+// usageError is returned for invalid commands, flags and arguments
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// errNotFound is returned when the requested resource does not exist
+var errNotFound = errors.New("not found")
+
+// options holds the flags shared by every command
+type options struct {
+	profile string
+	output  string
+	stdout  io.Writer
+	getenv  func(string) string
+
+	// baseURL overrides the API base URL when set
+	baseURL string
+}
+
+// register adds the shared flags to the given flag set
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.profile, "profile", o.profile, "credentials profile to use")
+	fs.StringVar(&o.output, "o", o.output, "output format: table, json or yaml")
+}
+
+// client resolves the credentials and returns a new Neustar object
+func (o *options) client() (*neustar.Neustar, error) {
+	key, secret, err := resolveCredentials(o.profile, o.getenv)
+	if err != nil {
+		return nil, err
+	}
+	n := neustar.NewNeustar(key, secret)
+	if o.baseURL != "" {
+		n.BaseURL = o.baseURL
+	}
+	return n, nil
+}
+
+// flagSet returns a new flag set for the given command with the shared flags
+func (o *options) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	o.register(fs)
+	return fs
+}
+
+// parse parses the given arguments, turning flag errors into usage errors
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return &usageError{fmt.Sprintf("%s: %s", fs.Name(), err)}
+	}
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run runs the command line with the given arguments and returns the exit code
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	o := &options{output: "table", stdout: stdout, getenv: getenv}
+	fs := o.flagSet("neustar")
+	if err := parse(fs, args); err != nil {
+		return fail(stderr, err)
+	}
+	if !validOutput(o.output) {
+		return fail(stderr, &usageError{fmt.Sprintf("unknown output format %q", o.output)})
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	var err error
+	switch args[0] {
+	case "monitors":
+		err = runMonitors(o, args[1:])
+	case "watch":
+		err = runWatch(o, args[1:])
+	case "help", "-h", "-help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		err = &usageError{fmt.Sprintf("unknown command %q", args[0])}
+	}
+	if err != nil {
+		return fail(stderr, err)
+	}
+	return exitOK
+}
+
+// fail prints the given error and returns the matching exit code
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "neustar: %s\n", err)
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprint(stderr, usage)
+	}
+	return code
+}
+
+// exitCode returns the exit code for the given error
+func exitCode(err error) int {
+	var ue *usageError
+	var ce *credentialsError
+	var re *neustar.ResponseError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &ue):
+		return exitUsage
+	case errors.As(err, &ce):
+		return exitCredentials
+	case errors.Is(err, errNotFound):
+		return exitNotFound
+	case errors.As(err, &re):
+		switch {
+		case re.StatusCode == 401 || re.StatusCode == 403:
+			return exitCredentials
+		case re.StatusCode == 404:
+			return exitNotFound
+		case re.StatusCode >= 500:
+			return exitAPIServer
+		}
+		return exitAPIClient
+	}
+	return exitError
+}
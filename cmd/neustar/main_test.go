@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/briandowns/neustar"
+)
+
+// env returns a getenv function backed by the given map
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+// TestRunUsage
+func TestRunUsage(t *testing.T) {
+	t.Parallel()
+
+	credentials := env(map[string]string{"NEUSTAR_KEY": "key", "NEUSTAR_SECRET": "secret"})
+	tests := []struct {
+		args   []string
+		getenv func(string) string
+		code   int
+		stderr string
+	}{
+		{nil, credentials, exitUsage, "usage:"},
+		{[]string{"help"}, credentials, exitOK, ""},
+		{[]string{"widgets"}, credentials, exitUsage, `unknown command "widgets"`},
+		{[]string{"-o", "xml", "monitors", "list"}, credentials, exitUsage, `unknown output format "xml"`},
+		{[]string{"monitors"}, credentials, exitUsage, "missing subcommand"},
+		{[]string{"monitors", "get"}, credentials, exitUsage, "requires <id>"},
+		{[]string{"monitors", "list", "extra"}, credentials, exitUsage, "unexpected arguments"},
+		{[]string{"monitors", "create", "-name", "home"}, credentials, exitUsage, "requires -name"},
+		{[]string{"monitors", "create", "-name", "home", "-script", "s1", "-locations", "atlantis"}, credentials, exitUsage, "atlantis is not a valid location"},
+		{[]string{"monitors", "update", "m1"}, credentials, exitUsage, "nothing to update"},
+		{[]string{"monitors", "update", "-interval", "7", "m1"}, credentials, exitUsage, "7 is not a valid interval"},
+		{[]string{"monitors", "list"}, env(nil), exitCredentials, "no credentials"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(test.args, &stdout, &stderr, test.getenv)
+		if code != test.code {
+			t.Errorf("%v: expected exit code %d, got %d", test.args, test.code, code)
+		}
+		if !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("%v: expected %q in stderr, got %q", test.args, test.stderr, stderr.String())
+		}
+	}
+}
+
+// TestExitCode
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{nil, exitOK},
+		{fmt.Errorf("dial tcp: connection refused"), exitError},
+		{&usageError{"bad flag"}, exitUsage},
+		{&credentialsError{"no credentials"}, exitCredentials},
+		{fmt.Errorf("monitor m1 %w", errNotFound), exitNotFound},
+		{&neustar.ResponseError{StatusCode: 403}, exitCredentials},
+		{&neustar.ResponseError{StatusCode: 404}, exitNotFound},
+		{&neustar.ResponseError{StatusCode: 400}, exitAPIClient},
+		{&neustar.ResponseError{StatusCode: 503}, exitAPIServer},
+	}
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.code {
+			t.Errorf("%v: expected exit code %d, got %d", test.err, test.code, code)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/briandowns/neustar"
+)
+
+// sampleDateFormat is the date format of the samples -start and -end flags
+const sampleDateFormat = "2006-01-02T15:04"
+
+// runMonitors runs the given monitors subcommand
+func runMonitors(o *options, args []string) error {
+	if len(args) == 0 {
+		return &usageError{"monitors: missing subcommand"}
+	}
+	switch args[0] {
+	case "list":
+		return monitorsList(o, args[1:])
+	case "get":
+		return monitorsGet(o, args[1:])
+	case "create":
+		return monitorsCreate(o, args[1:])
+	case "update":
+		return monitorsUpdate(o, args[1:])
+	case "delete":
+		return monitorsDelete(o, args[1:])
+	case "summary":
+		return monitorsSummary(o, args[1:])
+	case "samples":
+		return monitorsSamples(o, args[1:])
+	}
+	return &usageError{fmt.Sprintf("monitors: unknown subcommand %q", args[0])}
+}
+
+// parseArgs parses the given flags and checks the number of positional
+// arguments
+func parseArgs(fs *flag.FlagSet, args []string, positional ...string) error {
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == len(positional) {
+		return nil
+	}
+	if len(positional) == 0 {
+		return &usageError{fmt.Sprintf("%s: unexpected arguments", fs.Name())}
+	}
+	return &usageError{fmt.Sprintf("%s: requires <%s>", fs.Name(), strings.Join(positional, "> <"))}
+}
+
+// monitoring returns a Monitoring client
+func (o *options) monitoring() (*neustar.Monitoring, error) {
+	n, err := o.client()
+	if err != nil {
+		return nil, err
+	}
+	return neustar.NewMonitor(n), nil
+}
+
+func monitorsList(o *options, args []string) error {
+	if err := parseArgs(o.flagSet("monitors list"), args); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	monitors, err := m.List()
+	if err != nil {
+		return err
+	}
+	return write(o.stdout, o.output, monitors, monitorTable(monitors))
+}
+
+func monitorsGet(o *options, args []string) error {
+	fs := o.flagSet("monitors get")
+	if err := parseArgs(fs, args, "id"); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	monitors, err := m.Get(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(monitors) == 0 {
+		return fmt.Errorf("monitor %s %w", fs.Arg(0), errNotFound)
+	}
+	return write(o.stdout, o.output, monitors[0], monitorTable(monitors))
+}
+
+// monitorFlags holds the flags shared by create and update
+type monitorFlags struct {
+	name        string
+	description string
+	interval    int
+	script      string
+	locations   string
+	alertPolicy string
+	browser     string
+	monitorType string
+	active      bool
+}
+
+// register adds the monitor flags to the given flag set
+func (f *monitorFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.name, "name", "", "name of the monitor")
+	fs.StringVar(&f.description, "description", "", "description of the monitor")
+	fs.IntVar(&f.interval, "interval", 5, "minutes between samples")
+	fs.StringVar(&f.script, "script", "", "ID of the test script to run")
+	fs.StringVar(&f.locations, "locations", "", "comma separated locations to run from")
+	fs.StringVar(&f.alertPolicy, "alert-policy", "", "ID of the alert policy")
+	fs.StringVar(&f.browser, "browser", "", "browser to use: FF, CHROME or IE")
+	fs.StringVar(&f.monitorType, "type", "", "network monitor type such as dns")
+	fs.BoolVar(&f.active, "active", true, "whether the monitor takes samples")
+}
+
+// validate checks the values of the flags that were set
+func (f *monitorFlags) validate(set map[string]bool) error {
+	if set["interval"] && !neustar.ValidUpdateInterval(f.interval) {
+		return &usageError{fmt.Sprintf("%d is not a valid interval", f.interval)}
+	}
+	if set["locations"] {
+		for _, location := range strings.Split(f.locations, ",") {
+			if !neustar.ValidLocation(strings.TrimSpace(location)) {
+				return &usageError{fmt.Sprintf("%s is not a valid location", location)}
+			}
+		}
+	}
+	if set["browser"] && !neustar.ValidBrowserType(f.browser) {
+		return &usageError{fmt.Sprintf("%s is not a valid browser", f.browser)}
+	}
+	if set["type"] && !neustar.ValidMonitorType(f.monitorType) {
+		return &usageError{fmt.Sprintf("%s is not a valid monitor type", f.monitorType)}
+	}
+	return nil
+}
+
+// visited returns the names of the flags set on the command line
+func visited(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+func monitorsCreate(o *options, args []string) error {
+	fs := o.flagSet("monitors create")
+	var f monitorFlags
+	f.register(fs)
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	set := visited(fs)
+	set["interval"] = true
+	if f.name == "" || f.locations == "" || (f.script == "" && f.monitorType == "") {
+		return &usageError{"monitors create: requires -name, -locations and -script or -type"}
+	}
+	if err := f.validate(set); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	created, err := m.Create(&neustar.CreateMonitorParameters{
+		Name:        f.name,
+		Description: f.description,
+		Interval:    f.interval,
+		TestScript:  f.script,
+		Locations:   f.locations,
+		AlertPolicy: f.alertPolicy,
+		Browser:     f.browser,
+		Active:      strconv.FormatBool(f.active),
+		Type:        f.monitorType,
+	})
+	if err != nil {
+		return err
+	}
+	return write(o.stdout, o.output, created, table{
+		header: []string{"ID", "CREATED"},
+		rows:   [][]string{{created.ID, created.Created}},
+	})
+}
+
+func monitorsUpdate(o *options, args []string) error {
+	fs := o.flagSet("monitors update")
+	var f monitorFlags
+	f.register(fs)
+	if err := parseArgs(fs, args, "id"); err != nil {
+		return err
+	}
+	id := fs.Arg(0)
+	set := visited(fs)
+	if set["type"] {
+		return &usageError{"monitors update: the monitor type cannot be changed"}
+	}
+	if err := f.validate(set); err != nil {
+		return err
+	}
+	p := &neustar.UpdateMonitorParameters{}
+	if set["name"] {
+		p.Name = f.name
+	}
+	if set["description"] {
+		p.Description = f.description
+	}
+	if set["interval"] {
+		p.Interval = f.interval
+	}
+	if set["script"] {
+		p.TestScript = f.script
+	}
+	if set["locations"] {
+		p.Locations = f.locations
+	}
+	if set["alert-policy"] {
+		p.AlertPolicy = f.alertPolicy
+	}
+	if set["browser"] {
+		p.Browser = f.browser
+	}
+	if set["active"] {
+		p.Active = strconv.FormatBool(f.active)
+	}
+	if *p == (neustar.UpdateMonitorParameters{}) {
+		return &usageError{"monitors update: nothing to update"}
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	status, err := m.Update(id, p)
+	if err != nil {
+		return err
+	}
+	return write(o.stdout, o.output, map[string]interface{}{"id": id, "status": status}, table{
+		header: []string{"ID", "STATUS"},
+		rows:   [][]string{{id, strconv.Itoa(status)}},
+	})
+}
+
+func monitorsDelete(o *options, args []string) error {
+	fs := o.flagSet("monitors delete")
+	if err := parseArgs(fs, args, "id"); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	status, err := m.Delete(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return write(o.stdout, o.output, map[string]interface{}{"id": fs.Arg(0), "status": status}, table{
+		header: []string{"ID", "STATUS"},
+		rows:   [][]string{{fs.Arg(0), strconv.Itoa(status)}},
+	})
+}
+
+func monitorsSummary(o *options, args []string) error {
+	fs := o.flagSet("monitors summary")
+	if err := parseArgs(fs, args, "id"); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	summaries, err := m.Summary(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		return fmt.Errorf("summary of monitor %s %w", fs.Arg(0), errNotFound)
+	}
+	t := table{header: []string{"STATUS", "LAST SAMPLE", "LAST STATUS", "LOAD DAY", "UPTIME DAY", "TP50", "TP90", "LAST ERROR"}}
+	for _, s := range summaries {
+		t.rows = append(t.rows, []string{
+			s.Status,
+			s.LastSampleAt,
+			s.LastSampleStatus,
+			fmt.Sprintf("%dms", s.AvgLoadtimeDay),
+			fmt.Sprintf("%.2f%%", s.AvgUptimeDay),
+			fmt.Sprintf("%dms", s.TP50),
+			fmt.Sprintf("%dms", s.TP90),
+			s.LastErrorMessage,
+		})
+	}
+	return write(o.stdout, o.output, summaries[0], t)
+}
+
+func monitorsSamples(o *options, args []string) error {
+	fs := o.flagSet("monitors samples")
+	now := time.Now().UTC()
+	start := fs.String("start", now.Add(-time.Hour).Format(sampleDateFormat), "start of the period, such as 2015-10-01T12:00")
+	end := fs.String("end", now.Format(sampleDateFormat), "end of the period, such as 2015-10-01T13:00")
+	offset := fs.Int("offset", 0, "position in the list to start from")
+	if err := parseArgs(fs, args, "id"); err != nil {
+		return err
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	samples, err := m.Samples(fs.Arg(0), &neustar.SampleRequestParameters{
+		StartDate: *start,
+		EndDate:   *end,
+		Offset:    *offset,
+	})
+	if err != nil {
+		return err
+	}
+	items := samples.Data.Items
+	if items == nil {
+		items = []neustar.Sample{}
+	}
+	t := table{header: []string{"START", "STATUS", "LOCATION", "DURATION", "BYTES", "ID"}}
+	for _, s := range items {
+		t.rows = append(t.rows, []string{
+			s.StartTime,
+			s.Status,
+			s.Location,
+			fmt.Sprintf("%dms", s.Duration),
+			strconv.Itoa(s.BytesReceived),
+			s.ID,
+		})
+	}
+	return write(o.stdout, o.output, items, t)
+}
+
+// monitorTable returns a table of the given monitors
+func monitorTable(monitors []neustar.Monitor) table {
+	t := table{header: []string{"ID", "NAME", "TYPE", "INTERVAL", "ACTIVE", "LOCATIONS"}}
+	for _, m := range monitors {
+		t.rows = append(t.rows, []string{
+			m.ID,
+			m.Name,
+			m.Type,
+			fmt.Sprintf("%dm", m.Interval),
+			strconv.FormatBool(m.Active),
+			strings.Join(m.Locations, ","),
+		})
+	}
+	return t
+}
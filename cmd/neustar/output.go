@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// outputFormats is a slice of valid output formats
+var outputFormats = []string{"table", "json", "yaml"}
+
+// validOutput validates the given output format is valid
+func validOutput(format string) bool {
+	for _, i := range outputFormats {
+		if i == format {
+			return true
+		}
+	}
+	return false
+}
+
+// table holds the header and rows of a table
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// write writes v in the given format, or the given table for the table format
+func write(w io.Writer, format string, v interface{}, t table) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		return writeYAML(w, v)
+	}
+	return writeTable(w, t)
+}
+
+// writeTable writes the given table with aligned columns
+func writeTable(w io.Writer, t table) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeYAML writes v as YAML. v is converted through its JSON form so the
+// field names match the JSON output. Map keys are sorted.
+func writeYAML(w io.Writer, v interface{}) error {
+	buffer, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(buffer))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return err
+	}
+	lines := yamlLines(generic, 0)
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// yamlLines renders the given JSON value as YAML lines at the given indent
+func yamlLines(v interface{}, indent int) []string {
+	pad := strings.Repeat(" ", indent)
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return []string{pad + "{}"}
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var lines []string
+		for _, k := range keys {
+			child := value[k]
+			if yamlScalar(child) {
+				lines = append(lines, pad+yamlString(k)+": "+strings.TrimLeft(yamlLines(child, 0)[0], " "))
+				continue
+			}
+			lines = append(lines, pad+yamlString(k)+":")
+			lines = append(lines, yamlLines(child, indent+2)...)
+		}
+		return lines
+	case []interface{}:
+		if len(value) == 0 {
+			return []string{pad + "[]"}
+		}
+		var lines []string
+		for _, item := range value {
+			child := yamlLines(item, indent+2)
+			child[0] = pad + "- " + strings.TrimLeft(child[0], " ")
+			lines = append(lines, child...)
+		}
+		return lines
+	case string:
+		return []string{pad + yamlString(value)}
+	case json.Number:
+		return []string{pad + value.String()}
+	case bool:
+		return []string{pad + strconv.FormatBool(value)}
+	case nil:
+		return []string{pad + "null"}
+	}
+	return []string{pad + yamlString(fmt.Sprint(v))}
+}
+
+// yamlScalar returns true for values rendered on a single line
+func yamlScalar(v interface{}) bool {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	}
+	return true
+}
+
+// yamlString returns the given string, quoted if it would otherwise be read
+// back as something else
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "\n\t\"\\") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'%@`") {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWriteYAML
+func TestWriteYAML(t *testing.T) {
+	t.Parallel()
+
+	v := map[string]interface{}{
+		"name":      "home page",
+		"interval":  5,
+		"active":    true,
+		"version":   "1.0",
+		"note":      "",
+		"error":     "timeout: 30s",
+		"locations": []string{"dallas", "london"},
+		"empty":     []string{},
+		"steps": []map[string]interface{}{
+			{"name": "open", "duration": 120},
+			{"name": "yes", "tags": []string{"a"}},
+		},
+		"sla": map[string]interface{}{"uptime": 99.9},
+	}
+	var b bytes.Buffer
+	if err := writeYAML(&b, v); err != nil {
+		t.Fatal(err)
+	}
+	want := `active: true
+empty: []
+error: "timeout: 30s"
+interval: 5
+locations:
+  - dallas
+  - london
+name: home page
+note: ""
+sla:
+  uptime: 99.9
+steps:
+  - duration: 120
+    name: open
+  - name: "yes"
+    tags:
+      - a
+version: "1.0"
+`
+	if b.String() != want {
+		t.Errorf("unexpected YAML, got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+// TestWriteTable
+func TestWriteTable(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	err := write(&b, "table", nil, table{
+		header: []string{"ID", "NAME"},
+		rows:   [][]string{{"m1", "home"}, {"m22", "checkout"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ID   NAME\nm1   home\nm22  checkout\n"
+	if b.String() != want {
+		t.Errorf("unexpected table, got:\n%q\nwant:\n%q", b.String(), want)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/briandowns/neustar"
+)
+
+const (
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\x1b[H\x1b[2J"
+
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+// summaryWorkers is the number of summaries fetched at the same time
+const summaryWorkers = 8
+
+// dashboardRow holds the status of a single monitor
+type dashboardRow struct {
+	Name             string  `json:"name"`
+	Status           string  `json:"status"`
+	LastSampleStatus string  `json:"lastSampleStatus"`
+	LoadTime         int     `json:"loadTime"`
+	UptimeDay        float64 `json:"uptimeDay"`
+	LastError        string  `json:"lastError"`
+}
+
+// runWatch polls the monitors and renders a refreshing status table until
+// interrupted
+func runWatch(o *options, args []string) error {
+	fs := o.flagSet("watch")
+	interval := fs.Duration("interval", 30*time.Second, "time between refreshes")
+	tags := fs.String("tag", "", "comma separated tags, only monitors with one of them are shown")
+	once := fs.Bool("once", false, "render the table once and exit, honoring -o")
+	noColor := fs.Bool("no-color", o.getenv("NO_COLOR") != "", "disable colors")
+	if err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *interval < time.Second {
+		return &usageError{"watch: -interval must be at least 1s"}
+	}
+	if o.output != "table" && !*once {
+		return &usageError{fmt.Sprintf("watch: -o %s requires -once", o.output)}
+	}
+	m, err := o.monitoring()
+	if err != nil {
+		return err
+	}
+	var filter []string
+	if *tags != "" {
+		for _, tag := range strings.Split(*tags, ",") {
+			filter = append(filter, strings.TrimSpace(tag))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *once {
+		rows, err := pollDashboard(ctx, m, filter)
+		if err != nil {
+			return err
+		}
+		if o.output != "table" {
+			return write(o.stdout, o.output, rows, table{})
+		}
+		return renderDashboard(o.stdout, rows, !*noColor)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		rows, err := pollDashboard(ctx, m, filter)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Fprint(o.stdout, clearScreen)
+		fmt.Fprintf(o.stdout, "neustar watch: %d monitors, updated %s, every %s\n\n",
+			len(rows), time.Now().Format("15:04:05"), *interval)
+		if err != nil {
+			fmt.Fprintf(o.stdout, "error: %s\n", err)
+		} else if err := renderDashboard(o.stdout, rows, !*noColor); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollDashboard retrieves the monitors carrying any of the given tags, or all
+// monitors without tags, and their summaries. It returns as soon as the
+// context is cancelled, leaving any request in flight behind.
+func pollDashboard(ctx context.Context, m *neustar.Monitoring, tags []string) ([]dashboardRow, error) {
+	type result struct {
+		rows []dashboardRow
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		rows, err := fetchDashboard(ctx, m, tags)
+		done <- result{rows, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.rows, r.err
+	}
+}
+
+// fetchDashboard builds the dashboard rows. Once the context is cancelled it
+// starts no more summaries and returns without waiting for those in flight.
+func fetchDashboard(ctx context.Context, m *neustar.Monitoring, tags []string) ([]dashboardRow, error) {
+	monitors, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	monitors = filterMonitors(monitors, tags)
+
+	rows := make([]dashboardRow, len(monitors))
+	jobs := make(chan int)
+	done := make(chan struct{}, len(monitors))
+	for w := 0; w < summaryWorkers; w++ {
+		go func() {
+			for i := range jobs {
+				summaries, err := m.Summary(monitors[i].ID)
+				rows[i] = newDashboardRow(monitors[i], summaries, err)
+				done <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range monitors {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for range monitors {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	sortDashboard(rows)
+	return rows, nil
+}
+
+// newDashboardRow builds the row of the given monitor out of its summary.
+// A failed summary is shown in the last error column.
+func newDashboardRow(monitor neustar.Monitor, summaries []neustar.SummaryDataResponse, err error) dashboardRow {
+	row := dashboardRow{Name: monitor.Name, Status: "?"}
+	switch {
+	case err != nil:
+		row.LastError = "summary: " + err.Error()
+	case len(summaries) == 0:
+		row.LastError = "summary: no data"
+	default:
+		s := summaries[0]
+		row.Status = s.Status
+		row.LastSampleStatus = s.LastSampleStatus
+		row.LoadTime = s.LastSampleDuration
+		row.UptimeDay = s.AvgUptimeDay
+		row.LastError = s.LastErrorMessage
+	}
+	return row
+}
+
+// filterMonitors returns the monitors carrying any of the given tags, or all
+// monitors without tags
+func filterMonitors(monitors []neustar.Monitor, tags []string) []neustar.Monitor {
+	if len(tags) == 0 {
+		return monitors
+	}
+	var filtered []neustar.Monitor
+	for _, monitor := range monitors {
+		for _, tag := range monitor.Tags {
+			if contains(tags, tag) {
+				filtered = append(filtered, monitor)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// contains returns true if the given slice holds the given string
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRank orders alerting monitors first, then warning ones
+func statusRank(status string) int {
+	switch status {
+	case "Alerting":
+		return 0
+	case "Warning":
+		return 1
+	}
+	return 2
+}
+
+// sortDashboard sorts the rows by status and then name
+func sortDashboard(rows []dashboardRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		ri, rj := statusRank(rows[i].Status), statusRank(rows[j].Status)
+		if ri != rj {
+			return ri < rj
+		}
+		return rows[i].Name < rows[j].Name
+	})
+}
+
+// renderDashboard writes the rows as a table, coloring Alerting statuses red
+// and Warning statuses yellow. Padding is computed on the plain text so the
+// escape codes do not break the alignment.
+func renderDashboard(w io.Writer, rows []dashboardRow, color bool) error {
+	header := []string{"NAME", "STATUS", "LAST SAMPLE", "LOAD TIME", "UPTIME DAY", "LAST ERROR"}
+	cells := [][]string{header}
+	for _, row := range rows {
+		cells = append(cells, []string{
+			row.Name,
+			row.Status,
+			row.LastSampleStatus,
+			fmt.Sprintf("%dms", row.LoadTime),
+			fmt.Sprintf("%.2f%%", row.UptimeDay),
+			row.LastError,
+		})
+	}
+	widths := make([]int, len(header))
+	for _, line := range cells {
+		for i, cell := range line {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for n, line := range cells {
+		var paint string
+		if color && n > 0 {
+			switch rows[n-1].Status {
+			case "Alerting":
+				paint = colorRed
+			case "Warning":
+				paint = colorYellow
+			}
+		}
+		for i, cell := range line {
+			if i == len(line)-1 {
+				b.WriteString(cell)
+				break
+			}
+			padded := cell + strings.Repeat(" ", widths[i]-len(cell)+2)
+			if paint != "" && i == 1 {
+				padded = paint + cell + colorReset + strings.Repeat(" ", widths[i]-len(cell)+2)
+			}
+			b.WriteString(padded)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/briandowns/neustar"
+)
+
+// TestFilterMonitors
+func TestFilterMonitors(t *testing.T) {
+	t.Parallel()
+
+	monitors := []neustar.Monitor{
+		{ID: "m1", Tags: []string{"checkout", "prod"}},
+		{ID: "m2", Tags: []string{"staging"}},
+		{ID: "m3"},
+	}
+	if got := filterMonitors(monitors, nil); len(got) != 3 {
+		t.Errorf("expected every monitor without tags, got %d", len(got))
+	}
+	got := filterMonitors(monitors, []string{"prod", "qa"})
+	if len(got) != 1 || got[0].ID != "m1" {
+		t.Errorf("expected only m1, got %+v", got)
+	}
+}
+
+// TestNewDashboardRow
+func TestNewDashboardRow(t *testing.T) {
+	t.Parallel()
+
+	monitor := neustar.Monitor{Name: "home"}
+	row := newDashboardRow(monitor, []neustar.SummaryDataResponse{{
+		Status:             "Warning",
+		LastSampleStatus:   "SUCCESS",
+		LastSampleDuration: 1830,
+		AvgUptimeDay:       99.5,
+		LastErrorMessage:   "timeout",
+	}}, nil)
+	want := dashboardRow{Name: "home", Status: "Warning", LastSampleStatus: "SUCCESS", LoadTime: 1830, UptimeDay: 99.5, LastError: "timeout"}
+	if row != want {
+		t.Errorf("unexpected row %+v", row)
+	}
+
+	row = newDashboardRow(monitor, nil, errors.New("neustar: 503: Service Unavailable"))
+	if row.Status != "?" || row.LastError != "summary: neustar: 503: Service Unavailable" {
+		t.Errorf("expected the summary error in the row, got %+v", row)
+	}
+}
+
+// TestRenderDashboard
+func TestRenderDashboard(t *testing.T) {
+	t.Parallel()
+
+	rows := []dashboardRow{
+		{Name: "search", Status: "Active", LastSampleStatus: "SUCCESS", LoadTime: 900, UptimeDay: 100},
+		{Name: "home", Status: "Warning", LastSampleStatus: "SUCCESS", LoadTime: 4200, UptimeDay: 99.5},
+		{Name: "checkout", Status: "Alerting", LastSampleStatus: "ERROR", LoadTime: 30000, UptimeDay: 87.25, LastError: "timeout"},
+	}
+	sortDashboard(rows)
+
+	var b bytes.Buffer
+	if err := renderDashboard(&b, rows, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME      STATUS    LAST SAMPLE  LOAD TIME  UPTIME DAY  LAST ERROR\n" +
+		"checkout  Alerting  ERROR        30000ms    87.25%      timeout\n" +
+		"home      Warning   SUCCESS      4200ms     99.50%      \n" +
+		"search    Active    SUCCESS      900ms      100.00%     \n"
+	if b.String() != want {
+		t.Errorf("unexpected table, got:\n%q\nwant:\n%q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := renderDashboard(&b, rows, true); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	if !strings.Contains(lines[1], colorRed+"Alerting"+colorReset+"  ") ||
+		!strings.Contains(lines[2], colorYellow+"Warning"+colorReset+"   ") ||
+		strings.Contains(lines[3], "\x1b[") {
+		t.Errorf("unexpected colors:\n%q", b.String())
+	}
+}
+
+// watchServer serves two monitors, one tagged prod, and their summaries
+func watchServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/monitor/1.0":
+			w.Write([]byte(`{"data": {"items": [{"id": "m1", "name": "home", "tags": ["prod"]}, {"id": "m2", "name": "staging"}]}}`))
+		case "/monitor/1.0/m1/summary":
+			w.Write([]byte(`{"data": {"items": [{"status": "Warning", "lastSampleStatus": "SUCCESS", "lastSampleDuration": 1830, "avgUptimeDay": 99.5}]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestRunWatchUsage
+func TestRunWatchUsage(t *testing.T) {
+	t.Parallel()
+
+	credentials := env(map[string]string{"NEUSTAR_KEY": "key", "NEUSTAR_SECRET": "secret"})
+	tests := []struct {
+		args   []string
+		stderr string
+	}{
+		{[]string{"watch", "-interval", "500ms"}, "-interval must be at least 1s"},
+		{[]string{"watch", "-interval", "soon"}, "invalid value"},
+		{[]string{"-o", "json", "watch"}, "-o json requires -once"},
+		{[]string{"-o", "yaml", "watch", "-interval", "5s"}, "-o yaml requires -once"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(test.args, &stdout, &stderr, credentials); code != exitUsage {
+			t.Errorf("%v: expected exit code %d, got %d", test.args, exitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("%v: expected %q in stderr, got %q", test.args, test.stderr, stderr.String())
+		}
+	}
+}
+
+// TestRunWatchOnce
+func TestRunWatchOnce(t *testing.T) {
+	t.Parallel()
+
+	srv := watchServer(t)
+	credentials := env(map[string]string{"NEUSTAR_KEY": "key", "NEUSTAR_SECRET": "secret"})
+
+	var stdout bytes.Buffer
+	o := &options{output: "json", stdout: &stdout, getenv: credentials, baseURL: srv.URL + "/"}
+	if err := runWatch(o, []string{"-once", "-tag", "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	var rows []dashboardRow
+	if err := json.Unmarshal(stdout.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	want := []dashboardRow{{Name: "home", Status: "Warning", LastSampleStatus: "SUCCESS", LoadTime: 1830, UptimeDay: 99.5}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected %+v, got %+v", want, rows)
+	}
+
+	stdout.Reset()
+	o.output = "table"
+	if err := runWatch(o, []string{"-once", "-no-color", "-tag", "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(stdout.String(), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[1], "home  Warning") {
+		t.Errorf("unexpected table:\n%s", stdout.String())
+	}
+}
+
+// TestPollDashboardCancelled
+func TestPollDashboardCancelled(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	n := neustar.NewNeustar("key", "secret")
+	n.BaseURL = srv.URL + "/"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := pollDashboard(ctx, neustar.NewMonitor(n), nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the stalled poll to stop with the context, got %v", err)
+	}
+}
+
+// TestFetchDashboardStalledSummary
+func TestFetchDashboardStalledSummary(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/monitor/1.0" {
+			w.Write([]byte(`{"data": {"items": [{"id": "m1", "name": "checkout"}, {"id": "m2", "name": "search"}]}}`))
+			return
+		}
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	n := neustar.NewNeustar("key", "secret")
+	n.BaseURL = srv.URL + "/"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := fetchDashboard(ctx, neustar.NewMonitor(n), nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the stalled summaries to be abandoned with the context, got %v", err)
+	}
+}
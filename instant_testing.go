@@ -89,11 +89,14 @@ func (i *InstantTesting) GetJob(instantTestID string) (InstanceTestingResponse,
 	var response *http.Response
 	var data InstanceTestingResponse
 	response, err := http.Get(
-		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", BaseURL, ToolsURI, instantTestID, i.neustar.Key, i.neustar.DigitalSignature()))
+		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", i.neustar.baseURL(), ToolsURI, instantTestID, i.neustar.Key, i.neustar.DigitalSignature()))
 	if err != nil {
 		return InstanceTestingResponse{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return InstanceTestingResponse{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return InstanceTestingResponse{}, err
 	}
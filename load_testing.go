@@ -1 +1,273 @@
 package neustar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// LoadTestURI is the endpoint for calls to the load testing API
+	LoadTestURI = "load/1.0"
+
+	// ScheduleURI is the endpoint for scheduling a load test
+	ScheduleURI = "/schedule"
+
+	// CancelURI is the endpoint for cancelling a load test
+	CancelURI = "/cancel"
+
+	// ResultsURI is the endpoint for load test results
+	ResultsURI = "/results"
+
+	// LiveURI is the endpoint for the live metrics of a running load test
+	LiveURI = "/live"
+)
+
+// LoadTestStatuses is a slice of the statuses a load test can be in
+var LoadTestStatuses = []string{"draft", "scheduled", "running", "completed", "cancelled", "failed"}
+
+// LoadTestPhase holds a single phase of a load test user schedule. The number
+// of virtual users is ramped linearly to Users over Ramp minutes and then
+// held for Plateau minutes.
+type LoadTestPhase struct {
+	// The number of concurrent users at the end of the ramp
+	Users int `json:"users"`
+
+	// Minutes taken to reach Users from the previous phase
+	Ramp int `json:"ramp"`
+
+	// Minutes Users are held for
+	Plateau int `json:"plateau"`
+}
+
+// CreateLoadTestParameters holds the parameters needed to create a load test
+type CreateLoadTestParameters struct {
+	// The name of the load test
+	Name string `json:"name"`
+
+	// A description of the load test
+	Description string `json:"description,omitempty"`
+
+	// The ID of the script each virtual user runs
+	ScriptID string `json:"scriptId"`
+
+	// The locations virtual users are started from
+	Locations []string `json:"locations"`
+
+	// The user schedule of the load test
+	Schedule []LoadTestPhase `json:"userSchedule"`
+}
+
+// Validate makes sure the load test has a name, script, valid locations and
+// a user schedule
+func (p *CreateLoadTestParameters) Validate() error {
+	if p == nil {
+		return errors.New("neustar: load test parameters are required")
+	}
+	if p.Name == "" {
+		return errors.New("neustar: load test name is required")
+	}
+	if p.ScriptID == "" {
+		return errors.New("neustar: load test script is required")
+	}
+	if len(p.Locations) == 0 {
+		return errors.New("neustar: load test requires at least one location")
+	}
+	for _, location := range p.Locations {
+		if !ValidLocation(location) {
+			return fmt.Errorf("neustar: %s is not a valid location", location)
+		}
+	}
+	if len(p.Schedule) == 0 {
+		return errors.New("neustar: load test user schedule is empty")
+	}
+	for i, phase := range p.Schedule {
+		if phase.Users < 0 || phase.Ramp < 0 || phase.Plateau < 0 {
+			return fmt.Errorf("neustar: load test phase %d has negative values", i+1)
+		}
+		if phase.Ramp == 0 && phase.Plateau == 0 {
+			return fmt.Errorf("neustar: load test phase %d has no duration", i+1)
+		}
+	}
+	return nil
+}
+
+// LoadTest holds a load test
+type LoadTest struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	ScriptID    string          `json:"scriptId"`
+	Locations   []string        `json:"locations"`
+	Schedule    []LoadTestPhase `json:"userSchedule"`
+
+	// The status of the load test, one of LoadTestStatuses
+	Status string `json:"status"`
+
+	Created   Timestamp `json:"created"`
+	Scheduled Timestamp `json:"scheduled"`
+	Started   Timestamp `json:"started"`
+	Finished  Timestamp `json:"finished"`
+}
+
+// LoadTestResults holds the overall results of a load test
+type LoadTestResults struct {
+	LoadTestID string `json:"loadTestId"`
+	Status     string `json:"status"`
+
+	// The highest number of concurrent users reached
+	PeakUsers int `json:"peakUsers"`
+
+	// The number of requests made and how many failed
+	Requests       int `json:"requests"`
+	FailedRequests int `json:"failedRequests"`
+
+	// Response times in milliseconds
+	AvgResponseTime int `json:"avgResponseTime"`
+	TP50            int `json:"tp50"`
+	TP90            int `json:"tp90"`
+	TP99            int `json:"tp99"`
+
+	// Requests per second over the whole test
+	Throughput float64 `json:"throughput"`
+
+	// The results broken down by script step and by location
+	Steps     []LoadTestBreakdown `json:"steps,omitempty"`
+	Locations []LoadTestBreakdown `json:"locations,omitempty"`
+}
+
+// LoadTesting holds load testing config
+type LoadTesting struct {
+	neustar *Neustar
+}
+
+// NewLoadTest creates a new LoadTesting object
+func NewLoadTest(neustar *Neustar) *LoadTesting {
+	return &LoadTesting{
+		neustar: neustar,
+	}
+}
+
+// Create creates a new load test that runs the given script from the given
+// locations on the given user schedule. The test does not start until it is
+// scheduled. The test is checked against DefaultLoadTestLimits first.
+func (l *LoadTesting) Create(p *CreateLoadTestParameters) (LoadTest, error) {
+	if err := p.ValidateLimits(DefaultLoadTestLimits); err != nil {
+		return LoadTest{}, err
+	}
+	return l.send("POST", "", p)
+}
+
+// Schedule schedules the given load test to start at the given time
+func (l *LoadTesting) Schedule(id string, start time.Time) (LoadTest, error) {
+	return l.send("POST", fmt.Sprintf("/%s%s", id, ScheduleURI), map[string]Timestamp{"start": {start.UTC()}})
+}
+
+// Cancel cancels the given load test, stopping it if it is running
+func (l *LoadTesting) Cancel(id string) (LoadTest, error) {
+	return l.send("POST", fmt.Sprintf("/%s%s", id, CancelURI), struct{}{})
+}
+
+// send sends the given payload to the given load test endpoint
+func (l *LoadTesting) send(method, uri string, payload interface{}) (LoadTest, error) {
+	buffer, err := json.Marshal(payload)
+	if err != nil {
+		return LoadTest{}, err
+	}
+	body := bytes.NewBuffer(buffer)
+	var data map[string]map[string]LoadTest
+	request, err := http.NewRequest(
+		method,
+		fmt.Sprintf("%s%s%s?apikey=%s&sig=%s", l.neustar.baseURL(), LoadTestURI, uri, l.neustar.Key, l.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		return LoadTest{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return LoadTest{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return LoadTest{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return LoadTest{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// List retrieves a list of all load tests associated with your account
+func (l *LoadTesting) List() ([]LoadTest, error) {
+	var response *http.Response
+	var data map[string]map[string][]LoadTest
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s?apikey=%s&sig=%s",
+		l.neustar.baseURL(), LoadTestURI, l.neustar.Key, l.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data["data"]["items"], nil
+}
+
+// Get retrieves the given load test
+func (l *LoadTesting) Get(id string) (LoadTest, error) {
+	var response *http.Response
+	var data map[string]map[string]LoadTest
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s/%s?apikey=%s&sig=%s",
+		l.neustar.baseURL(), LoadTestURI, id, l.neustar.Key, l.neustar.DigitalSignature()))
+	if err != nil {
+		return LoadTest{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return LoadTest{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return LoadTest{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// Results retrieves the overall results of the given load test
+func (l *LoadTesting) Results(id string) (LoadTestResults, error) {
+	var response *http.Response
+	var data map[string]map[string]LoadTestResults
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s/%s%s?apikey=%s&sig=%s",
+		l.neustar.baseURL(), LoadTestURI, id, ResultsURI, l.neustar.Key, l.neustar.DigitalSignature()))
+	if err != nil {
+		return LoadTestResults{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return LoadTestResults{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return LoadTestResults{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// ValidLoadTestStatus validates the given load test status is valid
+func ValidLoadTestStatus(status string) bool {
+	for _, i := range LoadTestStatuses {
+		if i == status {
+			return true
+		}
+	}
+	return false
+}
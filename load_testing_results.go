@@ -0,0 +1,220 @@
+package neustar
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LoadTestBreakdown holds the results of a load test for a single step or
+// location
+type LoadTestBreakdown struct {
+	// The step name or location
+	Name string `json:"name"`
+
+	Requests        int     `json:"requests"`
+	FailedRequests  int     `json:"failedRequests"`
+	AvgResponseTime int     `json:"avgResponseTime"`
+	TP50            int     `json:"tp50"`
+	TP90            int     `json:"tp90"`
+	TP99            int     `json:"tp99"`
+	Throughput      float64 `json:"throughput"`
+}
+
+// ErrorRate returns the share of requests that failed
+func (b LoadTestBreakdown) ErrorRate() float64 {
+	return errorRate(b.FailedRequests, b.Requests)
+}
+
+// ErrorRate returns the share of requests that failed
+func (r LoadTestResults) ErrorRate() float64 {
+	return errorRate(r.FailedRequests, r.Requests)
+}
+
+// LoadTestSnapshot holds the live metrics of a running load test
+type LoadTestSnapshot struct {
+	// When the metrics were taken
+	Time Timestamp `json:"time"`
+
+	// The status of the load test
+	Status string `json:"status"`
+
+	// The number of concurrent users
+	Users int `json:"users"`
+
+	// Requests per second
+	Throughput float64 `json:"throughput"`
+
+	// The share of requests that failed, between 0 and 1
+	ErrorRate float64 `json:"errorRate"`
+
+	// Response time percentiles in milliseconds
+	TP50 int `json:"tp50"`
+	TP90 int `json:"tp90"`
+	TP99 int `json:"tp99"`
+}
+
+// Done returns true once the load test is no longer running
+func (s LoadTestSnapshot) Done() bool {
+	switch s.Status {
+	case "completed", "cancelled", "failed":
+		return true
+	}
+	return false
+}
+
+// Live retrieves the current metrics of the given load test. The request is
+// abandoned when the context is cancelled.
+func (l *LoadTesting) Live(ctx context.Context, id string) (LoadTestSnapshot, error) {
+	var data map[string]map[string]LoadTestSnapshot
+	request, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		fmt.Sprintf("%s%s/%s%s?apikey=%s&sig=%s", l.neustar.baseURL(), LoadTestURI, id, LiveURI, l.neustar.Key, l.neustar.DigitalSignature()),
+		nil)
+	if err != nil {
+		return LoadTestSnapshot{}, err
+	}
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return LoadTestSnapshot{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return LoadTestSnapshot{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return LoadTestSnapshot{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// Stream polls the live metrics of the given load test at the given interval
+// and passes each snapshot to fn until the test finishes, the context is
+// cancelled or fn returns an error. The interval must be positive.
+func (l *LoadTesting) Stream(ctx context.Context, id string, interval time.Duration, fn func(LoadTestSnapshot) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("neustar: stream interval must be positive, got %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		snapshot, err := l.Live(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(snapshot); err != nil {
+			return err
+		}
+		if snapshot.Done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadTestCSVHeader holds the columns written by LoadTestResults.WriteCSV
+var loadTestCSVHeader = []string{
+	"scope", "name", "requests", "failed_requests", "error_rate",
+	"avg_response_time_ms", "tp50_ms", "tp90_ms", "tp99_ms", "throughput_rps",
+}
+
+// WriteCSV writes the overall results followed by the per step and per
+// location breakdowns as CSV
+func (r LoadTestResults) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(loadTestCSVHeader); err != nil {
+		return err
+	}
+	overall := LoadTestBreakdown{
+		Name:            r.LoadTestID,
+		Requests:        r.Requests,
+		FailedRequests:  r.FailedRequests,
+		AvgResponseTime: r.AvgResponseTime,
+		TP50:            r.TP50,
+		TP90:            r.TP90,
+		TP99:            r.TP99,
+		Throughput:      r.Throughput,
+	}
+	if err := cw.Write(breakdownRecord("overall", overall)); err != nil {
+		return err
+	}
+	for _, step := range r.Steps {
+		if err := cw.Write(breakdownRecord("step", step)); err != nil {
+			return err
+		}
+	}
+	for _, location := range r.Locations {
+		if err := cw.Write(breakdownRecord("location", location)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the results as indented JSON
+func (r LoadTestResults) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteSnapshotsCSV writes the given snapshots as CSV, one row per snapshot
+func WriteSnapshotsCSV(w io.Writer, snapshots []LoadTestSnapshot) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "status", "users", "throughput_rps", "error_rate", "tp50_ms", "tp90_ms", "tp99_ms"}); err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		record := []string{
+			s.Time.Format(time.RFC3339),
+			s.Status,
+			strconv.Itoa(s.Users),
+			strconv.FormatFloat(s.Throughput, 'f', 2, 64),
+			strconv.FormatFloat(s.ErrorRate, 'f', 4, 64),
+			strconv.Itoa(s.TP50),
+			strconv.Itoa(s.TP90),
+			strconv.Itoa(s.TP99),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// breakdownRecord converts the given breakdown into a CSV record
+func breakdownRecord(scope string, b LoadTestBreakdown) []string {
+	return []string{
+		scope,
+		b.Name,
+		strconv.Itoa(b.Requests),
+		strconv.Itoa(b.FailedRequests),
+		strconv.FormatFloat(b.ErrorRate(), 'f', 4, 64),
+		strconv.Itoa(b.AvgResponseTime),
+		strconv.Itoa(b.TP50),
+		strconv.Itoa(b.TP90),
+		strconv.Itoa(b.TP99),
+		strconv.FormatFloat(b.Throughput, 'f', 2, 64),
+	}
+}
+
+// errorRate returns failed as a share of total
+func errorRate(failed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
@@ -0,0 +1,251 @@
+package neustar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoadTestLimits holds the platform limits a load test must stay within
+type LoadTestLimits struct {
+	// The most concurrent users across all locations
+	MaxUsers int
+
+	// The most concurrent users started from a single location
+	MaxUsersPerLocation int
+
+	// The longest a load test can run
+	MaxDuration time.Duration
+}
+
+// DefaultLoadTestLimits holds the limits checked by LoadTesting.Create. Change
+// them to match the limits of your account.
+var DefaultLoadTestLimits = LoadTestLimits{
+	MaxUsers:            50000,
+	MaxUsersPerLocation: 5000,
+	MaxDuration:         24 * time.Hour,
+}
+
+// LoadTestSchedule builds a load test user schedule from ramp, step, spike and
+// soak phases. Methods can be chained and the first error encountered is
+// returned by Build.
+type LoadTestSchedule struct {
+	phases []LoadTestPhase
+	users  int
+	err    error
+}
+
+// NewLoadTestSchedule creates a new LoadTestSchedule object starting at zero users
+func NewLoadTestSchedule() *LoadTestSchedule {
+	return &LoadTestSchedule{}
+}
+
+// Ramp linearly changes the number of users to the given number over the
+// given duration
+func (s *LoadTestSchedule) Ramp(users int, over time.Duration) *LoadTestSchedule {
+	return s.add(users, over, 0)
+}
+
+// Hold keeps the current number of users for the given duration
+func (s *LoadTestSchedule) Hold(d time.Duration) *LoadTestSchedule {
+	return s.add(s.users, 0, d)
+}
+
+// Step raises the number of users to the given number in the given number of
+// equal steps, holding each step for the given duration
+func (s *LoadTestSchedule) Step(users, steps int, each time.Duration) *LoadTestSchedule {
+	if steps < 1 {
+		return s.fail(fmt.Errorf("neustar: %d is not a valid number of steps", steps))
+	}
+	start := s.users
+	for i := 1; i <= steps; i++ {
+		s.add(start+(users-start)*i/steps, 0, each)
+	}
+	return s
+}
+
+// Spike ramps to the given number of users within a minute, holds them for
+// the given duration and returns to the previous number of users within a
+// minute
+func (s *LoadTestSchedule) Spike(users int, hold time.Duration) *LoadTestSchedule {
+	previous := s.users
+	s.add(users, time.Minute, hold)
+	return s.add(previous, time.Minute, 0)
+}
+
+// Soak ramps to the given number of users over the given ramp and holds them
+// for the given, usually long, duration
+func (s *LoadTestSchedule) Soak(users int, ramp, d time.Duration) *LoadTestSchedule {
+	return s.add(users, ramp, d)
+}
+
+// Build returns the phases of the schedule
+func (s *LoadTestSchedule) Build() ([]LoadTestPhase, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if len(s.phases) == 0 {
+		return nil, errors.New("neustar: load test schedule has no phases")
+	}
+	phases := make([]LoadTestPhase, len(s.phases))
+	copy(phases, s.phases)
+	return phases, nil
+}
+
+func (s *LoadTestSchedule) add(users int, ramp, plateau time.Duration) *LoadTestSchedule {
+	if s.err != nil {
+		return s
+	}
+	if users < 0 {
+		return s.fail(fmt.Errorf("neustar: %d is not a valid number of users", users))
+	}
+	if ramp < 0 || plateau < 0 {
+		return s.fail(errors.New("neustar: load test phase durations must not be negative"))
+	}
+	if ramp%time.Minute != 0 || plateau%time.Minute != 0 {
+		return s.fail(errors.New("neustar: load test phase durations must be whole minutes"))
+	}
+	if ramp == 0 && plateau == 0 {
+		return s.fail(errors.New("neustar: load test phase has no duration"))
+	}
+	s.phases = append(s.phases, LoadTestPhase{
+		Users:   users,
+		Ramp:    int(ramp / time.Minute),
+		Plateau: int(plateau / time.Minute),
+	})
+	s.users = users
+	return s
+}
+
+func (s *LoadTestSchedule) fail(err error) *LoadTestSchedule {
+	if s.err == nil {
+		s.err = err
+	}
+	return s
+}
+
+// ScheduleDuration returns how long the given user schedule runs for
+func ScheduleDuration(phases []LoadTestPhase) time.Duration {
+	var minutes int
+	for _, phase := range phases {
+		minutes += phase.Ramp + phase.Plateau
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// PeakUsers returns the highest number of concurrent users in the given
+// user schedule
+func PeakUsers(phases []LoadTestPhase) int {
+	var peak int
+	for _, phase := range phases {
+		if phase.Users > peak {
+			peak = phase.Users
+		}
+	}
+	return peak
+}
+
+// ValidateLimits makes sure the load test stays within the given limits,
+// spreading users evenly over its locations, and returns every problem found
+func (p *CreateLoadTestParameters) ValidateLimits(limits LoadTestLimits) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var problems []string
+	peak := PeakUsers(p.Schedule)
+	if limits.MaxUsers > 0 && peak > limits.MaxUsers {
+		problems = append(problems, fmt.Sprintf("peak of %d users is above the limit of %d", peak, limits.MaxUsers))
+	}
+	perLocation := (peak + len(p.Locations) - 1) / len(p.Locations)
+	if limits.MaxUsersPerLocation > 0 && perLocation > limits.MaxUsersPerLocation {
+		problems = append(problems, fmt.Sprintf("peak of %d users per location is above the limit of %d",
+			perLocation, limits.MaxUsersPerLocation))
+	}
+	duration := ScheduleDuration(p.Schedule)
+	if limits.MaxDuration > 0 && duration > limits.MaxDuration {
+		problems = append(problems, fmt.Sprintf("duration of %s is above the limit of %s", duration, limits.MaxDuration))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("neustar: load test exceeds platform limits: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// usersAt returns the number of users the schedule has at the given minute
+func usersAt(phases []LoadTestPhase, minute float64) float64 {
+	var users float64
+	var start float64
+	for _, phase := range phases {
+		target := float64(phase.Users)
+		ramp := float64(phase.Ramp)
+		if minute < start+ramp {
+			return users + (target-users)*(minute-start)/ramp
+		}
+		start += ramp
+		users = target
+		if minute < start+float64(phase.Plateau) {
+			return users
+		}
+		start += float64(phase.Plateau)
+	}
+	return users
+}
+
+// RenderSchedule draws the given user schedule as an ASCII chart of the given
+// width and height, not counting the axes and labels
+func RenderSchedule(phases []LoadTestPhase, width, height int) string {
+	if width < 1 {
+		width = 60
+	}
+	if height < 1 {
+		height = 10
+	}
+	total := ScheduleDuration(phases).Minutes()
+	peak := PeakUsers(phases)
+	if total == 0 || peak == 0 {
+		return "(empty schedule)\n"
+	}
+
+	// Each row is one step of peak/height users. Levels and the label of
+	// the bottom row are both rounded to the nearest step; the bottom row is
+	// labelled with at least 1 as 0 is the axis itself.
+	levels := make([]int, width)
+	for x := range levels {
+		minute := (float64(x) + 0.5) * total / float64(width)
+		levels[x] = roundNearest(usersAt(phases, minute) / float64(peak) * float64(height))
+	}
+	step := roundNearest(float64(peak) / float64(height))
+	if step < 1 {
+		step = 1
+	}
+	label := len(fmt.Sprint(peak))
+	var b strings.Builder
+	for y := height; y >= 1; y-- {
+		switch y {
+		case height:
+			fmt.Fprintf(&b, "%*d |", label, peak)
+		case 1:
+			fmt.Fprintf(&b, "%*d |", label, step)
+		default:
+			fmt.Fprintf(&b, "%*s |", label, "")
+		}
+		for _, level := range levels {
+			if level >= y {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%*s +%s\n", label, "", strings.Repeat("-", width))
+	end := fmt.Sprintf("%dm", int(total))
+	fmt.Fprintf(&b, "%*s  0%*s\n", label, "", width-1, end)
+	return b.String()
+}
+
+// roundNearest rounds the given non-negative value to the nearest integer
+func roundNearest(v float64) int {
+	return int(v + 0.5)
+}
@@ -1 +1,284 @@
 package neustar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewLoadTest
+func TestNewLoadTest(t *testing.T) {
+	t.Parallel()
+
+	l := NewLoadTest(setUp())
+
+	if reflect.TypeOf(l).String() != "*neustar.LoadTesting" {
+		t.Error("Incorrect data type pointer returned from NewLoadTest function")
+	}
+}
+
+// TestCreateLoadTestParametersValidate
+func TestCreateLoadTestParametersValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := CreateLoadTestParameters{
+		Name:      "black friday",
+		ScriptID:  "abc123",
+		Locations: []string{"dallas", "london"},
+		Schedule:  []LoadTestPhase{{Users: 100, Ramp: 10, Plateau: 30}, {Users: 0, Ramp: 5}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	invalid := map[string]func(p *CreateLoadTestParameters){
+		"no name":        func(p *CreateLoadTestParameters) { p.Name = "" },
+		"no script":      func(p *CreateLoadTestParameters) { p.ScriptID = "" },
+		"no locations":   func(p *CreateLoadTestParameters) { p.Locations = nil },
+		"bad location":   func(p *CreateLoadTestParameters) { p.Locations = []string{"atlantis"} },
+		"no schedule":    func(p *CreateLoadTestParameters) { p.Schedule = nil },
+		"empty phase":    func(p *CreateLoadTestParameters) { p.Schedule = []LoadTestPhase{{Users: 10}} },
+		"negative users": func(p *CreateLoadTestParameters) { p.Schedule = []LoadTestPhase{{Users: -1, Ramp: 1}} },
+	}
+	for name, mutate := range invalid {
+		p := valid
+		mutate(&p)
+		if err := p.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	if _, err := NewLoadTest(setUp()).Create(nil); err == nil {
+		t.Error("expected an error for nil parameters")
+	}
+}
+
+// TestLoadTestResultsWriteCSV
+func TestLoadTestResultsWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	results := LoadTestResults{
+		LoadTestID:      "lt1",
+		Requests:        1000,
+		FailedRequests:  25,
+		AvgResponseTime: 420,
+		TP50:            380,
+		TP90:            900,
+		TP99:            2100,
+		Throughput:      16.5,
+		Steps:           []LoadTestBreakdown{{Name: "Home", Requests: 500, FailedRequests: 5}},
+		Locations:       []LoadTestBreakdown{{Name: "dallas", Requests: 1000, FailedRequests: 25}},
+	}
+
+	var b bytes.Buffer
+	if err := results.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "scope,name,requests,failed_requests,error_rate,avg_response_time_ms,tp50_ms,tp90_ms,tp99_ms,throughput_rps\n" +
+		"overall,lt1,1000,25,0.0250,420,380,900,2100,16.50\n" +
+		"step,Home,500,5,0.0100,0,0,0,0,0.00\n" +
+		"location,dallas,1000,25,0.0250,0,0,0,0,0.00\n"
+	if b.String() != want {
+		t.Errorf("unexpected CSV, got:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	if rate := (LoadTestResults{}).ErrorRate(); rate != 0 {
+		t.Errorf("expected an error rate of 0 without requests, got %f", rate)
+	}
+}
+
+// TestLoadTestSchedule
+func TestLoadTestSchedule(t *testing.T) {
+	t.Parallel()
+
+	phases, err := NewLoadTestSchedule().
+		Ramp(100, 10*time.Minute).
+		Hold(20*time.Minute).
+		Step(400, 3, 5*time.Minute).
+		Spike(1000, 2*time.Minute).
+		Soak(200, 5*time.Minute, 60*time.Minute).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LoadTestPhase{
+		{Users: 100, Ramp: 10},
+		{Users: 100, Plateau: 20},
+		{Users: 200, Plateau: 5},
+		{Users: 300, Plateau: 5},
+		{Users: 400, Plateau: 5},
+		{Users: 1000, Ramp: 1, Plateau: 2},
+		{Users: 400, Ramp: 1},
+		{Users: 200, Ramp: 5, Plateau: 60},
+	}
+	if !reflect.DeepEqual(phases, want) {
+		t.Errorf("unexpected phases, got %+v", phases)
+	}
+	if d := ScheduleDuration(phases); d != 114*time.Minute {
+		t.Errorf("expected a duration of 114m, got %s", d)
+	}
+	if peak := PeakUsers(phases); peak != 1000 {
+		t.Errorf("expected a peak of 1000 users, got %d", peak)
+	}
+
+	for name, s := range map[string]*LoadTestSchedule{
+		"empty":          NewLoadTestSchedule(),
+		"partial minute": NewLoadTestSchedule().Ramp(10, 90*time.Second),
+		"negative users": NewLoadTestSchedule().Ramp(-1, time.Minute),
+		"no steps":       NewLoadTestSchedule().Step(10, 0, time.Minute),
+	} {
+		if _, err := s.Build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestValidateLimits
+func TestValidateLimits(t *testing.T) {
+	t.Parallel()
+
+	p := &CreateLoadTestParameters{
+		Name:      "peak",
+		ScriptID:  "abc123",
+		Locations: []string{"dallas", "london"},
+		Schedule:  []LoadTestPhase{{Users: 3000, Ramp: 10, Plateau: 60}},
+	}
+	limits := LoadTestLimits{MaxUsers: 5000, MaxUsersPerLocation: 2000, MaxDuration: time.Hour}
+
+	err := p.ValidateLimits(limits)
+	if err == nil {
+		t.Fatal("expected the duration limit to be exceeded")
+	}
+	if strings.Contains(err.Error(), "per location") || !strings.Contains(err.Error(), "duration of 1h10m0s") {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	p.Locations = []string{"dallas"}
+	if err := p.ValidateLimits(limits); err == nil || !strings.Contains(err.Error(), "3000 users per location") {
+		t.Errorf("expected the per location limit to be exceeded, got %v", err)
+	}
+}
+
+// TestRenderSchedule
+func TestRenderSchedule(t *testing.T) {
+	t.Parallel()
+
+	chart := RenderSchedule([]LoadTestPhase{{Users: 100, Ramp: 4}, {Users: 100, Plateau: 4}}, 8, 4)
+	want := "100 |   #####\n" +
+		"    |  ######\n" +
+		"    | #######\n" +
+		" 25 |########\n" +
+		"    +--------\n" +
+		"     0     8m\n"
+	if chart != want {
+		t.Errorf("unexpected chart, got:\n%s\nwant:\n%s", chart, want)
+	}
+
+	chart = RenderSchedule([]LoadTestPhase{{Users: 9, Plateau: 2}}, 2, 4)
+	if lines := strings.Split(chart, "\n"); lines[3] != "2 |##" {
+		t.Errorf("expected the bottom row to be labelled with the rounded step 2, got:\n%s", chart)
+	}
+
+	chart = RenderSchedule([]LoadTestPhase{{Users: 3, Plateau: 2}}, 2, 10)
+	if lines := strings.Split(chart, "\n"); lines[9] != "1 |##" {
+		t.Errorf("expected the bottom row to be labelled 1, got:\n%s", chart)
+	}
+}
+
+// TestLoadTestingErrorResponses
+func TestLoadTestingErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": "MON_0004", "message": "load test not found"}}`))
+	})
+	l := NewLoadTest(n)
+
+	calls := map[string]func() error{
+		"Schedule": func() error {
+			_, err := l.Schedule("lt1", time.Now())
+			return err
+		},
+		"Cancel": func() error {
+			_, err := l.Cancel("lt1")
+			return err
+		},
+		"List": func() error {
+			_, err := l.List()
+			return err
+		},
+		"Get": func() error {
+			_, err := l.Get("lt1")
+			return err
+		},
+		"Results": func() error {
+			_, err := l.Results("lt1")
+			return err
+		},
+	}
+	for name, call := range calls {
+		var re *ResponseError
+		if err := call(); !errors.As(err, &re) || re.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected a 404 ResponseError, got %v", name, err)
+		}
+	}
+}
+
+// TestStream
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	statuses := []string{"running", "running", "completed"}
+	var calls int
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/load/1.0/lt1/live" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprintf(w, `{"data": {"items": {"status": %q}}}`, statuses[calls])
+		calls++
+	})
+	l := NewLoadTest(n)
+
+	var seen []string
+	err := l.Stream(context.Background(), "lt1", time.Millisecond, func(s LoadTestSnapshot) error {
+		seen = append(seen, s.Status)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(seen, statuses) {
+		t.Errorf("expected %v, got %v", statuses, seen)
+	}
+
+	if err := l.Stream(context.Background(), "lt1", 0, nil); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
+
+// TestLiveContext
+func TestLiveContext(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := NewLoadTest(n).Live(ctx, "lt1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the stalled request to be abandoned, got %v", err)
+	}
+}
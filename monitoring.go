@@ -25,6 +25,10 @@ const (
 
 	// SamplesURI is the endpoint for sample calls
 	SamplesURI = "/sample"
+
+	// maxSamplesPerCall is the most samples the Samples endpoint returns at
+	// once
+	maxSamplesPerCall = 2000
 )
 
 // MonitorTypes is a slice of valid monitor types
@@ -66,7 +70,7 @@ func (m *Monitoring) Create(cmp *CreateMonitorParameters) (CreateMonitorResponse
 	var data map[string]map[string]CreateMonitorResponse
 	request, err := http.NewRequest(
 		"POST",
-		fmt.Sprintf("%s%s?apikey=%s&sig=%s", BaseURL, MonitorURI, m.neustar.Key, m.neustar.DigitalSignature()),
+		fmt.Sprintf("%s%s?apikey=%s&sig=%s", m.neustar.baseURL(), MonitorURI, m.neustar.Key, m.neustar.DigitalSignature()),
 		body)
 	if err != nil {
 		return CreateMonitorResponse{}, err
@@ -78,6 +82,9 @@ func (m *Monitoring) Create(cmp *CreateMonitorParameters) (CreateMonitorResponse
 		return CreateMonitorResponse{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return CreateMonitorResponse{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return CreateMonitorResponse{}, err
 	}
@@ -92,11 +99,14 @@ func (m *Monitoring) List() ([]Monitor, error) {
 	var data map[string]map[string][]Monitor
 	response, err := http.Get(fmt.Sprintf(
 		"%s%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, err
 	}
@@ -110,11 +120,14 @@ func (m *Monitoring) Get(id string) ([]Monitor, error) {
 	var data map[string]map[string][]Monitor
 	response, err := http.Get(fmt.Sprintf(
 		"%s%s/%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, id, m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, id, m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, err
 	}
@@ -123,22 +136,45 @@ func (m *Monitoring) Get(id string) ([]Monitor, error) {
 
 // Update changes some or all of the parameters of an existing monitor.
 // Requires the monitor ID retrieved from the List Monitors api.
-func (m *Monitoring) Update() {}
+func (m *Monitoring) Update(id string, ump *UpdateMonitorParameters) (int, error) {
+	buffer, err := json.Marshal(ump)
+	if err != nil {
+		return 0, err
+	}
+	request, err := http.NewRequest(
+		"PUT",
+		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", m.neustar.baseURL(), MonitorURI, id, m.neustar.Key, m.neustar.DigitalSignature()),
+		bytes.NewBuffer(buffer))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	return response.StatusCode, checkResponse(response)
+}
 
 // Delete deletes the given monitor, stopping it from monitoring and removing
 // all its monitoring data.
 func (m *Monitoring) Delete(id string) (int, error) {
-	var response *http.Response
-	response, err := http.Get(fmt.Sprintf(
-		"%s%s/%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, id, m.neustar.Key, m.neustar.DigitalSignature()))
+	request, err := http.NewRequest(
+		"DELETE",
+		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", m.neustar.baseURL(), MonitorURI, id, m.neustar.Key, m.neustar.DigitalSignature()),
+		nil)
 	if err != nil {
-		return response.StatusCode, err
+		return 0, err
 	}
-	if response.StatusCode != 200 {
-		return response.StatusCode, nil
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
 	}
-	return response.StatusCode, nil
+	defer response.Body.Close()
+	return response.StatusCode, checkResponse(response)
 }
 
 // RawSampleData retrieves the raw, HTTP Archive (HAR) data for a particular sample
@@ -147,11 +183,14 @@ func (m *Monitoring) RawSampleData(monitorID, sampleID string) (RawSampleDataRes
 	var data RawSampleDataResponse
 	response, err := http.Get(fmt.Sprintf(
 		"%s%s/%s/sample/%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, monitorID, sampleID, m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, monitorID, sampleID, m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return RawSampleDataResponse{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return RawSampleDataResponse{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return RawSampleDataResponse{}, err
 	}
@@ -175,17 +214,40 @@ func (m *Monitoring) Samples(monitorID string, srp *SampleRequestParameters) (Sa
 	var data SamplesDataResponse
 	response, err = http.Get(fmt.Sprintf(
 		"%s%s/%s%s?%s&apikey=%s&sig=%s",
-		BaseURL, MonitorURI, monitorID, SamplesURI, v.Encode(), m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, monitorID, SamplesURI, v.Encode(), m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return SamplesDataResponse{}, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return SamplesDataResponse{}, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return SamplesDataResponse{}, err
 	}
 	return data, nil
 }
 
+// allSamples pages through and retrieves every sample of the given monitor
+// for the given parameters
+func (m *Monitoring) allSamples(monitorID string, srp *SampleRequestParameters) ([]Sample, error) {
+	paged := *srp
+	var samples []Sample
+	err := pageAll(func(offset int) (int, bool, error) {
+		paged.Offset = srp.Offset + offset
+		data, err := m.Samples(monitorID, &paged)
+		if err != nil {
+			return 0, false, err
+		}
+		samples = append(samples, data.Data.Items...)
+		return len(data.Data.Items), len(data.Data.Items) >= maxSamplesPerCall, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
 // AggregateSampleData retrieves the aggregated sample information for a given period
 // of time. You can choose to aggregate the data for each hour or each day. This is
 // more effecient than getting all the individual samples for a period of time and
@@ -199,11 +261,14 @@ func (m *Monitoring) AggregateSampleData(monitorID string, asp *AggregateSampleP
 	var data AggregateSampleDataResponse
 	response, err = http.Get(fmt.Sprintf(
 		"%s%s/%s/%s?%s&apikey=%s&sig=%s",
-		BaseURL, MonitorURI, monitorID, AggregateURI, v.Encode(), m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, monitorID, AggregateURI, v.Encode(), m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, err
 	}
@@ -219,11 +284,14 @@ func (m *Monitoring) Summary(monitorID string) ([]SummaryDataResponse, error) {
 	var data map[string]map[string][]SummaryDataResponse
 	response, err := http.Get(fmt.Sprintf(
 		"%s%s/%s%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, monitorID, SummaryURI, m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, monitorID, SummaryURI, m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, err
 	}
@@ -236,11 +304,14 @@ func (m *Monitoring) Locations() ([]string, error) {
 	var data map[string]map[string][]string
 	response, err := http.Get(fmt.Sprintf(
 		"%s%s%s?apikey=%s&sig=%s",
-		BaseURL, MonitorURI, LocationsURI, m.neustar.Key, m.neustar.DigitalSignature()))
+		m.neustar.baseURL(), MonitorURI, LocationsURI, m.neustar.Key, m.neustar.DigitalSignature()))
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,254 @@
+package neustar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// MaintenanceURI is the endpoint for maintenance window calls
+	MaintenanceURI = "/maintenance"
+
+	// DefaultMaintenanceDuration is the length of the window opened by
+	// WithMaintenance when the context has no deadline
+	DefaultMaintenanceDuration = time.Hour
+)
+
+// MaintenanceRecurrences is a slice of valid maintenance window recurrences
+var MaintenanceRecurrences = []string{"once", "daily", "weekly", "monthly"}
+
+// MaintenanceWindowParameters holds the parameters needed to schedule a
+// maintenance window
+type MaintenanceWindowParameters struct {
+	// The name of the maintenance window
+	Name string `json:"name"`
+
+	// A description of the maintenance
+	Description string `json:"description,omitempty"`
+
+	// The IDs of the monitors in maintenance
+	MonitorIDs []string `json:"monitorIds,omitempty"`
+
+	// Monitors with any of these tags are in maintenance
+	Tags []string `json:"tags,omitempty"`
+
+	// When the window starts
+	Start Timestamp `json:"start"`
+
+	// The length of the window in minutes
+	Duration int `json:"duration"`
+
+	// How often the window repeats ('once', 'daily', 'weekly', 'monthly').
+	// Defaults to 'once'.
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+// Validate makes sure the window targets at least one monitor and has a valid
+// duration and recurrence
+func (mwp *MaintenanceWindowParameters) Validate() error {
+	if len(mwp.MonitorIDs) == 0 && len(mwp.Tags) == 0 {
+		return errors.New("neustar: maintenance window requires monitor IDs or tags")
+	}
+	if mwp.Start.IsZero() {
+		return errors.New("neustar: maintenance window requires a start time")
+	}
+	if mwp.Duration <= 0 {
+		return errors.New("neustar: maintenance window duration must be at least one minute")
+	}
+	if mwp.Recurrence != "" && !ValidMaintenanceRecurrence(mwp.Recurrence) {
+		return fmt.Errorf("neustar: %s is not a valid recurrence", mwp.Recurrence)
+	}
+	return nil
+}
+
+// MaintenanceWindow holds a scheduled maintenance window
+type MaintenanceWindow struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	MonitorIDs  []string  `json:"monitorIds"`
+	Tags        []string  `json:"tags"`
+	Start       Timestamp `json:"start"`
+	End         Timestamp `json:"end"`
+	Duration    int       `json:"duration"`
+	Recurrence  string    `json:"recurrence"`
+
+	// Whether the window is currently in effect
+	Active bool `json:"active"`
+}
+
+// ScheduleMaintenance schedules a one-off or recurring maintenance window.
+// Monitors in a maintenance window do not raise alerts.
+func (m *Monitoring) ScheduleMaintenance(mwp *MaintenanceWindowParameters) (MaintenanceWindow, error) {
+	if err := mwp.Validate(); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	buffer, err := json.Marshal(mwp)
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	body := bytes.NewBuffer(buffer)
+	var data map[string]map[string]MaintenanceWindow
+	request, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s%s%s?apikey=%s&sig=%s", m.neustar.baseURL(), MonitorURI, MaintenanceURI, m.neustar.Key, m.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	window := data["data"]["items"]
+	if window.ID == "" {
+		return MaintenanceWindow{}, errors.New("neustar: API returned no maintenance window ID")
+	}
+	return window, nil
+}
+
+// ListMaintenanceWindows retrieves all scheduled and active maintenance windows
+func (m *Monitoring) ListMaintenanceWindows() ([]MaintenanceWindow, error) {
+	var response *http.Response
+	var data map[string]map[string][]MaintenanceWindow
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s%s?apikey=%s&sig=%s",
+		m.neustar.baseURL(), MonitorURI, MaintenanceURI, m.neustar.Key, m.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data["data"]["items"], nil
+}
+
+// CancelMaintenance cancels the given maintenance window, ending it if it is
+// in effect, and returns the status code of the response
+func (m *Monitoring) CancelMaintenance(id string) (int, error) {
+	request, err := http.NewRequest(
+		"DELETE",
+		fmt.Sprintf("%s%s%s/%s?apikey=%s&sig=%s", m.neustar.baseURL(), MonitorURI, MaintenanceURI, id, m.neustar.Key, m.neustar.DigitalSignature()),
+		nil)
+	if err != nil {
+		return 0, err
+	}
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	return response.StatusCode, checkResponse(response)
+}
+
+// WithMaintenance puts the given monitors, given by ID or tag, in a maintenance
+// window while fn runs. The window lasts until the context deadline, or
+// DefaultMaintenanceDuration if there is none, and is always cancelled once fn
+// returns or panics.
+func (m *Monitoring) WithMaintenance(ctx context.Context, monitors []string, fn func(context.Context) error) (err error) {
+	monitorIDs, err := m.resolveMonitors(monitors)
+	if err != nil {
+		return err
+	}
+
+	duration := DefaultMaintenanceDuration
+	if deadline, ok := ctx.Deadline(); ok {
+		duration = time.Until(deadline)
+	}
+	minutes := int((duration + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	window, err := m.ScheduleMaintenance(&MaintenanceWindowParameters{
+		Name:       fmt.Sprintf("deploy %s", time.Now().UTC().Format(time.RFC3339)),
+		MonitorIDs: monitorIDs,
+		Start:      Timestamp{time.Now().UTC()},
+		Duration:   minutes,
+		Recurrence: "once",
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, cerr := m.CancelMaintenance(window.ID); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// resolveMonitors returns the IDs of the monitors having any of the given IDs
+// or tags. Every ID or tag must match at least one monitor.
+func (m *Monitoring) resolveMonitors(monitors []string) ([]string, error) {
+	if len(monitors) == 0 {
+		return nil, errors.New("neustar: maintenance window requires monitor IDs or tags")
+	}
+	all, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool, len(monitors))
+	var ids []string
+	for _, monitor := range all {
+		selected := false
+		for _, want := range monitors {
+			if monitor.ID == want || hasTag(monitor.Tags, want) {
+				matched[want] = true
+				selected = true
+			}
+		}
+		if selected {
+			ids = append(ids, monitor.ID)
+		}
+	}
+	for _, want := range monitors {
+		if !matched[want] {
+			return nil, fmt.Errorf("neustar: no monitor has the ID or tag %s", want)
+		}
+	}
+	return ids, nil
+}
+
+// hasTag reports whether the given tags hold the given tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidMaintenanceRecurrence validates the given recurrence is valid
+func ValidMaintenanceRecurrence(recurrence string) bool {
+	for _, i := range MaintenanceRecurrences {
+		if i == recurrence {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package neustar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMaintenanceWindowParametersValidate
+func TestMaintenanceWindowParametersValidate(t *testing.T) {
+	t.Parallel()
+
+	start := Timestamp{time.Date(2015, 10, 5, 22, 0, 0, 0, time.UTC)}
+
+	valid := &MaintenanceWindowParameters{Name: "release", Tags: []string{"web"}, Start: start, Duration: 30, Recurrence: "weekly"}
+	if err := valid.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	invalid := []*MaintenanceWindowParameters{
+		{Name: "no targets", Start: start, Duration: 30},
+		{Name: "no start", MonitorIDs: []string{"m1"}, Duration: 30},
+		{Name: "no duration", MonitorIDs: []string{"m1"}, Start: start},
+		{Name: "bad recurrence", MonitorIDs: []string{"m1"}, Start: start, Duration: 30, Recurrence: "hourly"},
+	}
+	for _, mwp := range invalid {
+		if err := mwp.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", mwp.Name)
+		}
+	}
+}
+
+// TestWithMaintenanceScheduleFails
+func TestWithMaintenanceScheduleFails(t *testing.T) {
+	t.Parallel()
+
+	responses := map[int]string{
+		http.StatusInternalServerError: `{"error": {"code": "MON_9999", "message": "internal error"}}`,
+		http.StatusOK:                  `{"data": {"items": {}}}`,
+	}
+	for status, body := range responses {
+		n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" && r.URL.Path == "/monitor/1.0" {
+				w.Write([]byte(`{"data": {"items": [{"id": "m1"}]}}`))
+				return
+			}
+			if r.Method != "POST" || r.URL.Path != "/monitor/1.0/maintenance" {
+				t.Errorf("%d: unexpected request %s %s", status, r.Method, r.URL)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+		ran := false
+		err := NewMonitor(n).WithMaintenance(context.Background(), []string{"m1"}, func(context.Context) error {
+			ran = true
+			return nil
+		})
+		if err == nil || ran {
+			t.Errorf("%d: expected an error without running fn, got %v (ran %t)", status, err, ran)
+		}
+	}
+}
+
+// TestWithMaintenance
+func TestWithMaintenance(t *testing.T) {
+	t.Parallel()
+
+	var cancelled string
+	var scheduled MaintenanceWindowParameters
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"data": {"items": [{"id": "m1"}, {"id": "m2", "tags": ["web"]}, {"id": "m3", "tags": ["db"]}]}}`))
+		case "POST":
+			json.NewDecoder(r.Body).Decode(&scheduled)
+			w.Write([]byte(`{"data": {"items": {"id": "w1"}}}`))
+		case "DELETE":
+			cancelled = r.URL.Path
+		}
+	})
+	m := NewMonitor(n)
+	ran := false
+	err := m.WithMaintenance(context.Background(), []string{"m1", "web"}, func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if err != nil || !ran {
+		t.Fatalf("expected fn to run without error, got %v (ran %t)", err, ran)
+	}
+	if strings.Join(scheduled.MonitorIDs, ",") != "m1,m2" {
+		t.Errorf("expected monitors m1 and m2 in maintenance, got %v", scheduled.MonitorIDs)
+	}
+	if cancelled != "/monitor/1.0/maintenance/w1" {
+		t.Errorf("expected window w1 to be cancelled, got %q", cancelled)
+	}
+
+	ran = false
+	err = m.WithMaintenance(context.Background(), []string{"api"}, func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if err == nil || ran {
+		t.Errorf("expected an unknown tag to fail without running fn, got %v (ran %t)", err, ran)
+	}
+}
+
+// TestListMaintenanceWindowsError
+func TestListMaintenanceWindowsError(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "MON_0011", "message": "User does not have permission to perform the action"}}`))
+	})
+	if windows, err := NewMonitor(n).ListMaintenanceWindows(); err == nil || windows != nil {
+		t.Errorf("expected an error and no windows, got %v", err)
+	}
+}
@@ -1,16 +1,16 @@
 package neustar
 
 // UpdateMonitorParameters holds the allowed options for updating
-// a monitor
+// a monitor. Only the fields set are changed.
 type UpdateMonitorParameters struct {
-	Name        string `json:"name"`
-	Description string `json:"name"`
-	Interval    int    `json:"interval"`
-	TestScript  string `json:"testScript"`
-	Locations   string `json:"locations"`
-	AlertPolicy string `json:"alertPolicy"`
-	Browser     string `json:"browser"`
-	Active      string `json:"Active"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Interval    int    `json:"interval,omitempty"`
+	TestScript  string `json:"testScript,omitempty"`
+	Locations   string `json:"locations,omitempty"`
+	AlertPolicy string `json:"alertPolicy,omitempty"`
+	Browser     string `json:"browser,omitempty"`
+	Active      string `json:"active,omitempty"`
 }
 
 // CreateMonitorParameters holds the parameters needed by the create
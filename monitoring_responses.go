@@ -80,23 +80,20 @@ type AggregateSampleResponse struct {
 // SamplesDataResponse holds a response from a call to the Samples endpoint
 type SamplesDataResponse struct {
 	Data struct {
-		Count int `json:"count"`
-		Items []struct {
-			Status          string `json:"status"`
-			BytesReceived   int    `json:"bytesReceived"`
-			ErrorLineNumber int    `json:"errorLineNumber"`
-			Location        string `json:"location"`
-			StartTime       string `json:"startTime"`
-			Duration        int    `json:"duration"`
-			ID              string `json:"id"`
-		} `json:"items"`
+		Count int      `json:"count"`
+		Items []Sample `json:"items"`
 	} `json:"data"`
 }
 
-// AlertPolicy holds the alert policy data
-type AlertPolicy struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+// Sample holds the high level timing of a single monitoring sample
+type Sample struct {
+	Status          string `json:"status"`
+	BytesReceived   int    `json:"bytesReceived"`
+	ErrorLineNumber int    `json:"errorLineNumber"`
+	Location        string `json:"location"`
+	StartTime       string `json:"startTime"`
+	Duration        int    `json:"duration"`
+	ID              string `json:"id"`
 }
 
 // Monitor hold monitoring data
@@ -139,6 +136,9 @@ type Monitor struct {
 	// The type of monitor ('RealBrowserUser', 'VirtualUser', 'dns')
 	Type string `json:"type"`
 
+	// The tags used to group this monitor
+	Tags []string `json:"tags,omitempty"`
+
 	SMTPSettings SMTPSettings `json:"smtpSettings"`
 	SLASettings  SLASettings  `json:"slaSettings,omitempty"`
 	DNSSettings  DNSSettings  `json:"dnsSettings,omitempty"`
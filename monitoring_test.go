@@ -1,6 +1,9 @@
 package neustar
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -143,3 +146,35 @@ func TestValidAggregateSampleDataFrequency(t *testing.T) {
 func TestValidAggregateSampleGroupBy(t *testing.T) {
 	t.Parallel()
 }
+
+// TestUpdateAndDelete
+func TestUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/monitor/1.0/m1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case "PUT":
+			var ump UpdateMonitorParameters
+			if err := json.NewDecoder(r.Body).Decode(&ump); err != nil || ump.Name != "renamed" {
+				t.Errorf("unexpected update body %+v, %v", ump, err)
+			}
+			w.Write([]byte(`{"data": {}}`))
+		case "DELETE":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error": {"code": "MON_0001", "message": "Item with Id m1 not found"}}`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	m := NewMonitor(n)
+	if status, err := m.Update("m1", &UpdateMonitorParameters{Name: "renamed"}); err != nil || status != 200 {
+		t.Errorf("expected the update to succeed, got %d, %v", status, err)
+	}
+	status, err := m.Delete("m1")
+	if status != 404 || !errors.Is(err, &ResponseError{ReturnedAPIError: ReturnedAPIError{Code: "MON_0001"}}) {
+		t.Errorf("expected a not found error, got %d, %v", status, err)
+	}
+}
@@ -2,8 +2,11 @@ package neustar
 
 import (
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,18 +25,119 @@ type ReturnedAPIError struct {
 	Param   string `json:"param"`
 }
 
+// ResponseError is returned by every service when the API responds with an
+// error; RUMError is an alias of it. Use errors.Is with a ResponseError holding
+// only a Code or a StatusCode to match a specific error.
+type ResponseError struct {
+	// The HTTP status code of the response
+	StatusCode int
+
+	// The error returned by the API, if any
+	ReturnedAPIError
+}
+
+// Error describes the error using the message returned by the API, falling
+// back to the description of the code and then the HTTP status
+func (e *ResponseError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = MonitoringErrorCodes[e.Code]
+	}
+	if message == "" {
+		message = RealUserMeasurementsErrorCodes[e.Code]
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	var prefix []string
+	if e.StatusCode != 0 {
+		prefix = append(prefix, strconv.Itoa(e.StatusCode))
+	}
+	if e.Code != "" {
+		prefix = append(prefix, e.Code)
+	}
+	if e.Param != "" {
+		message = fmt.Sprintf("%s (%s)", message, e.Param)
+	}
+	return fmt.Sprintf("neustar: %s: %s", strings.Join(prefix, " "), message)
+}
+
+// Is reports whether the target is a ResponseError with the same code or, if
+// the target has no code, the same status code
+func (e *ResponseError) Is(target error) bool {
+	t, ok := target.(*ResponseError)
+	if !ok {
+		return false
+	}
+	if t.Code != "" {
+		return t.Code == e.Code
+	}
+	return t.StatusCode != 0 && t.StatusCode == e.StatusCode
+}
+
+// Temporary returns true for errors worth retrying
+func (e *ResponseError) Temporary() bool {
+	switch e.Code {
+	case "RUM_000", "RUM_001", "RUM_002", "MON_9999":
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// checkResponse returns a *ResponseError if the response has an error status
+func checkResponse(response *http.Response) error {
+	if response.StatusCode < 400 {
+		return nil
+	}
+	var data struct {
+		Error ReturnedAPIError `json:"error"`
+	}
+	json.NewDecoder(response.Body).Decode(&data)
+	return &ResponseError{StatusCode: response.StatusCode, ReturnedAPIError: data.Error}
+}
+
+// pageAll calls page with the offset of each page until it reports there are
+// no more results. page returns the number of results on the page and
+// whether more are available.
+func pageAll(page func(offset int) (int, bool, error)) error {
+	offset := 0
+	for {
+		n, more, err := page(offset)
+		if err != nil {
+			return err
+		}
+		if !more || n == 0 {
+			return nil
+		}
+		offset += n
+	}
+}
+
 // Neustar holds the provided access keys
 type Neustar struct {
 	Key    string
 	Secret string
+
+	// BaseURL is the endpoint every service sends requests to. It defaults
+	// to the package BaseURL and can be pointed at a test server.
+	BaseURL string
 }
 
 // NewNeustar creates a new Neustar object
 func NewNeustar(key, secret string) *Neustar {
 	return &Neustar{
-		Key:    key,
-		Secret: secret,
+		Key:     key,
+		Secret:  secret,
+		BaseURL: BaseURL,
+	}
+}
+
+// baseURL returns the endpoint to send requests to
+func (n *Neustar) baseURL() string {
+	if n.BaseURL == "" {
+		return BaseURL
 	}
+	return n.BaseURL
 }
 
 // DigitalSignature creates an MD5 hash of the key, the secret and a timestamp
@@ -48,6 +152,60 @@ func (n *Neustar) DigitalSignature() string {
 	return fmt.Sprintf("%x", data)
 }
 
+// timestampLayouts holds the layouts the API is known to return dates in
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Timestamp holds a date returned from the API. The API returns dates either
+// as ISO 8601 strings or as milliseconds since the epoch.
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON parses the given date in any of the formats returned by the API
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	raw := strings.Trim(string(b), `"`)
+	if raw == "null" {
+		raw = ""
+	}
+	parsed, err := ParseTimestamp(raw)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// ParseTimestamp parses a date in any of the formats returned by the API. An
+// empty string returns the zero time.
+func ParseTimestamp(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond)).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("neustar: unable to parse timestamp %q", raw)
+}
+
+// MarshalJSON returns the timestamp as an RFC 3339 string
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.Format(time.RFC3339))), nil
+}
+
 // APIError represents what the API returns on error
 var APIError map[string]ReturnedAPIError
 
@@ -1 +1,198 @@
 package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestTimestampUnmarshalJSON
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	want := time.Date(2015, 10, 5, 14, 20, 12, 0, time.UTC)
+	inputs := []string{
+		`"2015-10-05T14:20:12Z"`,
+		`"2015-10-05T14:20:12"`,
+		`"2015-10-05 14:20:12"`,
+		`1444054812000`,
+	}
+
+	for _, input := range inputs {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(input), &ts); err != nil {
+			t.Errorf("%s: %s", input, err)
+			continue
+		}
+		if !ts.Equal(want) {
+			t.Errorf("%s: expected %s, got %s", input, want, ts.Time)
+		}
+	}
+
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`null`), &ts); err != nil || !ts.IsZero() {
+		t.Errorf("expected null to unmarshal to a zero timestamp, got %s, %v", ts.Time, err)
+	}
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("expected an error for an unparseable timestamp")
+	}
+}
+
+// TestCheckResponse
+func TestCheckResponse(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	w.WriteString(`{"data": {"items": []}}`)
+	if err := checkResponse(w.Result()); err != nil {
+		t.Errorf("expected no error for a 200 response, got %s", err)
+	}
+
+	w = httptest.NewRecorder()
+	w.WriteHeader(404)
+	w.WriteString(`{"error": {"code": "MON_0001", "message": "Item with Id m1 not found"}}`)
+	err := checkResponse(w.Result())
+	re, ok := err.(*ResponseError)
+	if !ok || re.StatusCode != 404 || re.Code != "MON_0001" {
+		t.Fatalf("expected a 404 *ResponseError, got %#v", err)
+	}
+	if re.Error() != "neustar: 404 MON_0001: Item with Id m1 not found" {
+		t.Errorf("unexpected message %q", re.Error())
+	}
+
+	w = httptest.NewRecorder()
+	w.WriteHeader(502)
+	if err := checkResponse(w.Result()); err == nil || err.Error() != "neustar: 502: Bad Gateway" {
+		t.Errorf("expected a bad gateway error, got %v", err)
+	}
+}
+
+// newTestNeustar returns a Neustar object pointed at a test server running
+// the given handler
+func newTestNeustar(t *testing.T, handler http.HandlerFunc) *Neustar {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	n := NewNeustar("key", "secret")
+	n.BaseURL = srv.URL + "/"
+	return n
+}
+
+// TestNeustarBaseURL
+func TestNeustarBaseURL(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/monitor/1.0/m1/summary" || r.URL.Query().Get("apikey") != "key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"data": {"items": [{"status": "Active"}]}}`))
+	})
+	summaries, err := NewMonitor(n).Summary("m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].Status != "Active" {
+		t.Errorf("unexpected summaries %+v", summaries)
+	}
+
+	if (&Neustar{}).baseURL() != BaseURL {
+		t.Error("expected the package BaseURL when none is set")
+	}
+}
+
+// TestResponseError
+func TestResponseError(t *testing.T) {
+	t.Parallel()
+
+	err := &ResponseError{StatusCode: 400, ReturnedAPIError: ReturnedAPIError{Code: "RUM_006", Param: "interval"}}
+	if err.Error() != "neustar: 400 RUM_006: Parameter is not valid (interval)" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	if !errors.Is(err, ErrRUMInvalidParameter) || errors.Is(err, ErrRUMForbidden) {
+		t.Error("expected the error to match by code")
+	}
+	if !errors.Is(err, &ResponseError{StatusCode: 400}) || errors.Is(err, &ResponseError{StatusCode: 404}) {
+		t.Error("expected the error to match by status code")
+	}
+	if err.Temporary() {
+		t.Error("expected an invalid parameter not to be temporary")
+	}
+	if !(&ResponseError{StatusCode: 503}).Temporary() || !ErrRUMThrottled.Temporary() {
+		t.Error("expected unavailable and throttled errors to be temporary")
+	}
+}
+
+// TestServicesReturnResponseError
+func TestServicesReturnResponseError(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": {"code": "RUM_000", "message": "Too many requests"}}`))
+	})
+	calls := map[string]func() error{
+		"Monitoring": func() error {
+			_, err := NewMonitor(n).List()
+			return err
+		},
+		"Alerting": func() error {
+			_, err := NewAlerting(n).ListAlertPolicies()
+			return err
+		},
+		"Scripting": func() error {
+			_, err := NewScript(n).Get("s1")
+			return err
+		},
+		"LoadTesting": func() error {
+			_, err := NewLoadTest(n).List()
+			return err
+		},
+		"InstantTesting": func() error {
+			_, err := NewInstantTest(n).GetJob("j1")
+			return err
+		},
+		"RealUserMeasurements": func() error {
+			_, _, err := NewRealUserMeasurements(n).Metrics(&RUMQuery{StartDate: "2015-10-01", EndDate: "2015-10-02"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		var re *ResponseError
+		if !errors.As(err, &re) || !errors.Is(err, ErrRUMThrottled) || !re.Temporary() {
+			t.Errorf("%s: expected a temporary RUM_000 ResponseError, got %v", name, err)
+		}
+	}
+}
+
+// TestPageAll
+func TestPageAll(t *testing.T) {
+	t.Parallel()
+
+	var offsets []int
+	err := pageAll(func(offset int) (int, bool, error) {
+		offsets = append(offsets, offset)
+		return 2, offset < 4, nil
+	})
+	if err != nil || fmt.Sprint(offsets) != "[0 2 4]" {
+		t.Errorf("expected offsets [0 2 4], got %v and %v", offsets, err)
+	}
+
+	calls := 0
+	err = pageAll(func(offset int) (int, bool, error) {
+		calls++
+		return 0, true, nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("expected an empty page to stop paging, got %d calls and %v", calls, err)
+	}
+
+	failure := errors.New("page failed")
+	if err := pageAll(func(int) (int, bool, error) { return 1, true, failure }); err != failure {
+		t.Errorf("expected the page error, got %v", err)
+	}
+}
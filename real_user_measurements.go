@@ -1 +1,228 @@
 package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	// RUMURI is the endpoint for calls to the real user measurements API
+	RUMURI = "rum/1.0"
+
+	// MetricsURI is the endpoint for page load metrics
+	MetricsURI = "/metrics"
+)
+
+// RUMGroupBy is a slice of valid groupBy parameters for RUM queries
+var RUMGroupBy = []string{"page", "country", "browser", "device"}
+
+// RUMIntervals is a slice of valid intervals for RUM queries
+var RUMIntervals = []string{"minute", "hour", "day"}
+
+// RUM errors returned by the API. errors.Is matches them by code rather than
+// by identity, so any RUMError with the same code matches, such as one
+// created by the caller. They are not meant to be modified or reassigned.
+var (
+	ErrRUMThrottled        = rumError("RUM_000")
+	ErrRUMDatabase         = rumError("RUM_001")
+	ErrRUMInternal         = rumError("RUM_002")
+	ErrRUMInconsistentData = rumError("RUM_003")
+	ErrRUMForbidden        = rumError("RUM_004")
+	ErrRUMMissingParameter = rumError("RUM_005")
+	ErrRUMInvalidParameter = rumError("RUM_006")
+)
+
+// RUMError is the error returned by the real user measurements API. It is the
+// same ResponseError returned by every other service.
+type RUMError = ResponseError
+
+// errRUMQueryRequired is returned by calls made without a query
+var errRUMQueryRequired = errors.New("neustar: RUM query is required")
+
+// rumError returns a RUMError matching the given code
+func rumError(code string) *RUMError {
+	return &RUMError{ReturnedAPIError: ReturnedAPIError{Code: code}}
+}
+
+// RUMQuery holds the options for querying page load metrics
+type RUMQuery struct {
+	// An ISO 8601 formatted date string or datetime string representing the
+	// start of the period. Examples: 2012-03-02 or 2012-03-01T12:00
+	StartDate string `url:"startDate"`
+
+	// An ISO 8601 formatted date string or datetime string representing the
+	// end of the period. Examples: 2012-03-02 or 2012-03-01T12:00
+	EndDate string `url:"endDate"`
+
+	// The length of each time bucket ('minute', 'hour', 'day')
+	Interval string `url:"interval,omitempty"`
+
+	// Splits the metrics by 'page', 'country', 'browser' or 'device'
+	GroupBy string `url:"groupBy,omitempty"`
+
+	// Filters the metrics to a single page URL
+	Page string `url:"page,omitempty"`
+
+	// Filters the metrics to a single ISO 3166 country code
+	Country string `url:"country,omitempty"`
+
+	// Filters the metrics to a single browser
+	Browser string `url:"browser,omitempty"`
+
+	// Filters the metrics to a single device type
+	Device string `url:"device,omitempty"`
+
+	// From which position in the return list you wish to start
+	Offset int `url:"offset"`
+}
+
+// Validate makes sure the query has a period and a valid interval and groupBy
+func (q *RUMQuery) Validate() error {
+	if q.StartDate == "" || q.EndDate == "" {
+		return errors.New("neustar: RUM query requires a start and end date")
+	}
+	if q.Interval != "" && !ValidRUMInterval(q.Interval) {
+		return fmt.Errorf("neustar: %s is not a valid interval", q.Interval)
+	}
+	if q.GroupBy != "" && !ValidRUMGroupBy(q.GroupBy) {
+		return fmt.Errorf("neustar: %s is not a valid groupBy", q.GroupBy)
+	}
+	return nil
+}
+
+// RUMMetric holds the page load metrics of a single time bucket and group
+type RUMMetric struct {
+	// The start of the time bucket
+	Time Timestamp `json:"time"`
+
+	// The page, country, browser or device of the group. Empty when the
+	// query is not grouped.
+	Group string `json:"group,omitempty"`
+
+	// The number of page views measured
+	PageViews int `json:"pageViews"`
+
+	// Page load times in milliseconds
+	AvgLoadTime int `json:"avgLoadTime"`
+	TP50        int `json:"tp50"`
+	TP75        int `json:"tp75"`
+	TP90        int `json:"tp90"`
+	TP95        int `json:"tp95"`
+
+	// Average navigation timings in milliseconds
+	AvgTTFB           int `json:"avgTtfb"`
+	AvgDOMInteractive int `json:"avgDomInteractive"`
+}
+
+// RUMDataResponse holds the return from the API metrics call
+type RUMDataResponse struct {
+	Data struct {
+		Total  int         `json:"total"`
+		Offset int         `json:"offset"`
+		More   bool        `json:"more"`
+		Items  []RUMMetric `json:"items"`
+	} `json:"data"`
+
+	// Set when the API returns an error
+	Error *ReturnedAPIError `json:"error,omitempty"`
+}
+
+// RealUserMeasurements holds real user measurements config
+type RealUserMeasurements struct {
+	neustar *Neustar
+}
+
+// NewRealUserMeasurements creates a new RealUserMeasurements object
+func NewRealUserMeasurements(neustar *Neustar) *RealUserMeasurements {
+	return &RealUserMeasurements{
+		neustar: neustar,
+	}
+}
+
+// Metrics retrieves a page of page load metrics for the given query. If more
+// is true, make another call with the offset set to the number of results
+// returned so far. Errors returned by the API are of type *ResponseError.
+func (r *RealUserMeasurements) Metrics(q *RUMQuery) ([]RUMMetric, bool, error) {
+	if q == nil {
+		return nil, false, errRUMQueryRequired
+	}
+	if err := q.Validate(); err != nil {
+		return nil, false, err
+	}
+	v, err := query.Values(q)
+	if err != nil {
+		return nil, false, err
+	}
+	var response *http.Response
+	response, err = http.Get(fmt.Sprintf(
+		"%s%s%s?%s&apikey=%s&sig=%s",
+		r.neustar.baseURL(), RUMURI, MetricsURI, v.Encode(), r.neustar.Key, r.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+	data, err := decodeRUMResponse(response)
+	if err != nil {
+		return nil, false, err
+	}
+	return data.Data.Items, data.Data.More, nil
+}
+
+// AllMetrics pages through and retrieves every metric for the given query
+func (r *RealUserMeasurements) AllMetrics(q *RUMQuery) ([]RUMMetric, error) {
+	if q == nil {
+		return nil, errRUMQueryRequired
+	}
+	paged := *q
+	var metrics []RUMMetric
+	err := pageAll(func(offset int) (int, bool, error) {
+		paged.Offset = q.Offset + offset
+		page, more, err := r.Metrics(&paged)
+		metrics = append(metrics, page...)
+		return len(page), more, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// decodeRUMResponse decodes the given response, turning API errors into a
+// *ResponseError
+func decodeRUMResponse(response *http.Response) (RUMDataResponse, error) {
+	if err := checkResponse(response); err != nil {
+		return RUMDataResponse{}, err
+	}
+	var data RUMDataResponse
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return RUMDataResponse{}, err
+	}
+	if data.Error != nil {
+		return RUMDataResponse{}, &ResponseError{StatusCode: response.StatusCode, ReturnedAPIError: *data.Error}
+	}
+	return data, nil
+}
+
+// ValidRUMGroupBy validates the given groupBy is valid
+func ValidRUMGroupBy(groupBy string) bool {
+	for _, i := range RUMGroupBy {
+		if i == groupBy {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidRUMInterval validates the given interval is valid
+func ValidRUMInterval(interval string) bool {
+	for _, i := range RUMIntervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
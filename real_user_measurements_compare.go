@@ -0,0 +1,419 @@
+package neustar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCoverageDistance is the distance in kilometers within which a
+// monitoring location is considered to cover a real user region
+const DefaultCoverageDistance = 1500
+
+// DefaultSlowTP90 is the real user TP90 page load time in milliseconds above
+// which a region is considered slow
+const DefaultSlowTP90 = 4000
+
+// GeoPoint holds a latitude and longitude in degrees
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// Distance returns the great circle distance in kilometers between two points
+func (p GeoPoint) Distance(q GeoPoint) float64 {
+	const earthRadius = 6371
+	rad := math.Pi / 180
+	dLat := (q.Lat - p.Lat) * rad
+	dLon := (q.Lon - p.Lon) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(p.Lat*rad)*math.Cos(q.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// LocationCoordinates holds the approximate position of each monitoring location
+var LocationCoordinates = map[string]GeoPoint{
+	"akron":        {41.08, -81.52},
+	"albuquerque":  {35.08, -106.65},
+	"amsterdam":    {52.37, 4.90},
+	"annapolis":    {38.98, -76.49},
+	"atlanta":      {33.75, -84.39},
+	"auckland":     {-36.85, 174.76},
+	"austin":       {30.27, -97.74},
+	"bangalore":    {12.97, 77.59},
+	"barcelona":    {41.39, 2.17},
+	"bedford":      {42.49, -71.28},
+	"beijing":      {39.90, 116.41},
+	"berlin":       {52.52, 13.40},
+	"birmingham":   {52.49, -1.89},
+	"boise":        {43.62, -116.20},
+	"boston":       {42.36, -71.06},
+	"brisbane":     {-27.47, 153.03},
+	"brussels":     {50.85, 4.35},
+	"bucharest":    {44.43, 26.10},
+	"budapest":     {47.50, 19.04},
+	"buenosaires":  {-34.60, -58.38},
+	"cairo":        {30.04, 31.24},
+	"calgary":      {51.05, -114.07},
+	"capetown":     {-33.92, 18.42},
+	"charlotte":    {35.23, -80.84},
+	"chicago":      {41.88, -87.63},
+	"cleveland":    {41.50, -81.69},
+	"columbus":     {39.96, -83.00},
+	"copenhagen":   {55.68, 12.57},
+	"dallas":       {32.78, -96.80},
+	"delhi":        {28.70, 77.10},
+	"denver":       {39.74, -104.99},
+	"detroit":      {42.33, -83.05},
+	"dubai":        {25.20, 55.27},
+	"dublin":       {53.35, -6.26},
+	"edinburgh":    {55.95, -3.19},
+	"frankfurt":    {50.11, 8.68},
+	"guangzhou":    {23.13, 113.26},
+	"halifax":      {44.65, -63.58},
+	"hamburg":      {53.55, 9.99},
+	"hartford":     {41.76, -72.67},
+	"helsinki":     {60.17, 24.94},
+	"hongkong":     {22.32, 114.17},
+	"honolulu":     {21.31, -157.86},
+	"houston":      {29.76, -95.37},
+	"istanbul":     {41.01, 28.98},
+	"kansascity":   {39.10, -94.58},
+	"kualalumpur":  {3.14, 101.69},
+	"lasvegas":     {36.17, -115.14},
+	"lisbon":       {38.72, -9.14},
+	"london":       {51.51, -0.13},
+	"losangeles":   {34.05, -118.24},
+	"madrid":       {40.42, -3.70},
+	"manchester":   {53.48, -2.24},
+	"melbourne":    {-37.81, 144.96},
+	"miami":        {25.76, -80.19},
+	"milan":        {45.46, 9.19},
+	"milwaukee":    {43.04, -87.91},
+	"minneapolis":  {44.98, -93.27},
+	"missoula":     {46.87, -113.99},
+	"montreal":     {45.50, -73.57},
+	"mumbai":       {19.08, 72.88},
+	"munich":       {48.14, 11.58},
+	"nagoya":       {35.18, 136.91},
+	"neworleans":   {29.95, -90.07},
+	"newark":       {40.74, -74.17},
+	"newyork":      {40.71, -74.01},
+	"omaha":        {41.26, -95.93},
+	"oslo":         {59.91, 10.75},
+	"paloalto":     {37.44, -122.14},
+	"paris":        {48.86, 2.35},
+	"philadelphia": {39.95, -75.17},
+	"phoenix":      {33.45, -112.07},
+	"pittsburgh":   {40.44, -79.99},
+	"portland":     {45.52, -122.68},
+	"portoalegre":  {-30.03, -51.23},
+	"prague":       {50.08, 14.44},
+	"raleigh":      {35.78, -78.64},
+	"rotterdam":    {51.92, 4.48},
+	"saltlakecity": {40.76, -111.89},
+	"sandiego":     {32.72, -117.16},
+	"sanfrancisco": {37.77, -122.42},
+	"sanjose":      {37.34, -121.89},
+	"saopaulo":     {-23.55, -46.63},
+	"scranton":     {41.41, -75.66},
+	"seattle":      {47.61, -122.33},
+	"shanghai":     {31.23, 121.47},
+	"singapore":    {1.35, 103.82},
+	"siouxfalls":   {43.54, -96.73},
+	"stlouis":      {38.63, -90.20},
+	"stockholm":    {59.33, 18.07},
+	"sydney":       {-33.87, 151.21},
+	"taipei":       {25.03, 121.57},
+	"tampa":        {27.95, -82.46},
+	"telaviv":      {32.09, 34.78},
+	"tokyo":        {35.68, 139.69},
+	"toronto":      {43.65, -79.38},
+	"vancouver":    {49.28, -123.12},
+	"warsaw":       {52.23, 21.01},
+	"washingtondc": {38.91, -77.04},
+	"zurich":       {47.38, 8.54},
+}
+
+// CountryCoordinates holds the approximate population center of countries by
+// ISO 3166 alpha-2 code, as returned by RUM queries grouped by country
+var CountryCoordinates = map[string]GeoPoint{
+	"AE": {25.20, 55.27},
+	"AR": {-34.60, -58.38},
+	"AT": {48.21, 16.37},
+	"AU": {-33.87, 151.21},
+	"BD": {23.81, 90.41},
+	"BE": {50.85, 4.35},
+	"BG": {42.70, 23.32},
+	"BR": {-23.55, -46.63},
+	"CA": {43.65, -79.38},
+	"CH": {47.38, 8.54},
+	"CL": {-33.45, -70.67},
+	"CN": {31.23, 121.47},
+	"CO": {4.71, -74.07},
+	"CZ": {50.08, 14.44},
+	"DE": {50.11, 8.68},
+	"DK": {55.68, 12.57},
+	"EG": {30.04, 31.24},
+	"ES": {40.42, -3.70},
+	"FI": {60.17, 24.94},
+	"FR": {48.86, 2.35},
+	"GB": {51.51, -0.13},
+	"GR": {37.98, 23.73},
+	"HK": {22.32, 114.17},
+	"HU": {47.50, 19.04},
+	"ID": {-6.21, 106.85},
+	"IE": {53.35, -6.26},
+	"IL": {32.09, 34.78},
+	"IN": {19.08, 72.88},
+	"IT": {45.46, 9.19},
+	"JP": {35.68, 139.69},
+	"KE": {-1.29, 36.82},
+	"KR": {37.57, 126.98},
+	"MX": {19.43, -99.13},
+	"MY": {3.14, 101.69},
+	"NG": {6.52, 3.38},
+	"NL": {52.37, 4.90},
+	"NO": {59.91, 10.75},
+	"NZ": {-36.85, 174.76},
+	"PE": {-12.05, -77.04},
+	"PH": {14.60, 120.98},
+	"PK": {24.86, 67.01},
+	"PL": {52.23, 21.01},
+	"PT": {38.72, -9.14},
+	"RO": {44.43, 26.10},
+	"RU": {55.76, 37.62},
+	"SA": {24.71, 46.68},
+	"SE": {59.33, 18.07},
+	"SG": {1.35, 103.82},
+	"TH": {13.76, 100.50},
+	"TR": {41.01, 28.98},
+	"TW": {25.03, 121.57},
+	"UA": {50.45, 30.52},
+	"US": {39.83, -98.58},
+	"VN": {10.82, 106.63},
+	"ZA": {-26.20, 28.05},
+}
+
+// NearestLocation returns the location out of the given locations closest to
+// the given point and its distance in kilometers. Locations without known
+// coordinates are skipped; ok is false if none are left.
+func NearestLocation(p GeoPoint, locations []string) (location string, distance float64, ok bool) {
+	distance = math.Inf(1)
+	for _, l := range locations {
+		c, found := LocationCoordinates[l]
+		if !found {
+			continue
+		}
+		if d := p.Distance(c); d < distance {
+			location, distance, ok = l, d, true
+		}
+	}
+	return location, distance, ok
+}
+
+// RUMComparisonOptions holds the thresholds used to build a RUMComparison
+type RUMComparisonOptions struct {
+	// The distance in kilometers within which a monitoring location covers a
+	// region. Defaults to DefaultCoverageDistance.
+	CoverageDistance float64
+
+	// The real user TP90 in milliseconds above which a region is slow.
+	// Defaults to DefaultSlowTP90.
+	SlowTP90 int
+}
+
+// RUMComparisonRow compares the real user page load times of a single country
+// with the synthetic load times of the monitoring location closest to it
+type RUMComparisonRow struct {
+	// The ISO 3166 country code
+	Country string
+
+	// Real user page views and page load percentiles in milliseconds
+	PageViews int
+	RUMTP50   int
+	RUMTP90   int
+
+	// The closest of all monitoring locations, which the monitor could be
+	// run from to cover the country
+	NearestLocation string
+
+	// The closest location the monitor runs from and its distance in kilometers
+	Location string
+	Distance float64
+
+	// Synthetic load percentiles in milliseconds measured from Location
+	SyntheticTP50 int
+	SyntheticTP90 int
+
+	// Whether Location is within the coverage distance
+	Covered bool
+
+	// Whether the real user TP90 is above the slow threshold
+	Slow bool
+}
+
+// Gap returns true for slow countries the monitor does not cover
+func (r RUMComparisonRow) Gap() bool {
+	return r.Slow && !r.Covered
+}
+
+// RUMComparison aligns real user and synthetic page load times for a page
+// over a period, per country
+type RUMComparison struct {
+	Page      string
+	StartDate string
+	EndDate   string
+	Rows      []RUMComparisonRow
+}
+
+// Gaps returns the rows of slow countries the monitor does not cover
+func (c RUMComparison) Gaps() []RUMComparisonRow {
+	var gaps []RUMComparisonRow
+	for _, row := range c.Rows {
+		if row.Gap() {
+			gaps = append(gaps, row)
+		}
+	}
+	return gaps
+}
+
+// String renders the comparison as a table, marking coverage gaps with '!'
+func (c RUMComparison) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s - %s\n", c.Page, c.StartDate, c.EndDate)
+	fmt.Fprintf(&b, "  %-7s %8s %8s %8s  %-14s %7s %8s %8s  %s\n",
+		"country", "views", "rum p50", "rum p90", "location", "km", "syn p50", "syn p90", "nearest")
+	for _, row := range c.Rows {
+		mark := " "
+		if row.Gap() {
+			mark = "!"
+		}
+		location := row.Location
+		if location == "" {
+			location = "-"
+		}
+		fmt.Fprintf(&b, "%s %-7s %8d %8d %8d  %-14s %7.0f %8d %8d  %s\n",
+			mark, row.Country, row.PageViews, row.RUMTP50, row.RUMTP90,
+			location, row.Distance, row.SyntheticTP50, row.SyntheticTP90, row.NearestLocation)
+	}
+	return b.String()
+}
+
+// NewRUMComparison builds a comparison out of RUM metrics grouped by country
+// and aggregate sample data grouped by location, taken from a monitor running
+// from the given locations. Percentiles over several time buckets are
+// weighted by page views and sample counts. Rows are sorted slowest first.
+func NewRUMComparison(page string, locations []string, rum []RUMMetric, synthetic []AggregateSampleResponse, opts *RUMComparisonOptions) RUMComparison {
+	coverage := float64(DefaultCoverageDistance)
+	slow := DefaultSlowTP90
+	if opts != nil {
+		if opts.CoverageDistance > 0 {
+			coverage = opts.CoverageDistance
+		}
+		if opts.SlowTP90 > 0 {
+			slow = opts.SlowTP90
+		}
+	}
+
+	type percentiles struct{ weight, tp50, tp90 int }
+	countries := make(map[string]*percentiles)
+	for _, metric := range rum {
+		p, ok := countries[metric.Group]
+		if !ok {
+			p = &percentiles{}
+			countries[metric.Group] = p
+		}
+		p.weight += metric.PageViews
+		p.tp50 += metric.TP50 * metric.PageViews
+		p.tp90 += metric.TP90 * metric.PageViews
+	}
+	sites := make(map[string]*percentiles)
+	for _, sample := range synthetic {
+		p, ok := sites[sample.Location]
+		if !ok {
+			p = &percentiles{}
+			sites[sample.Location] = p
+		}
+		p.weight += sample.Count
+		p.tp50 += sample.TP50 * sample.Count
+		p.tp90 += sample.TP90 * sample.Count
+	}
+
+	comparison := RUMComparison{Page: page}
+	for country, p := range countries {
+		if p.weight == 0 {
+			continue
+		}
+		row := RUMComparisonRow{
+			Country:   country,
+			PageViews: p.weight,
+			RUMTP50:   p.tp50 / p.weight,
+			RUMTP90:   p.tp90 / p.weight,
+		}
+		row.Slow = row.RUMTP90 > slow
+		if point, ok := CountryCoordinates[strings.ToUpper(country)]; ok {
+			row.NearestLocation, _, _ = NearestLocation(point, Locations)
+			if location, distance, ok := NearestLocation(point, locations); ok {
+				row.Location = location
+				row.Distance = distance
+				row.Covered = distance <= coverage
+				if s, ok := sites[location]; ok && s.weight > 0 {
+					row.SyntheticTP50 = s.tp50 / s.weight
+					row.SyntheticTP90 = s.tp90 / s.weight
+				}
+			}
+		}
+		comparison.Rows = append(comparison.Rows, row)
+	}
+	sort.Slice(comparison.Rows, func(i, j int) bool {
+		if comparison.Rows[i].RUMTP90 != comparison.Rows[j].RUMTP90 {
+			return comparison.Rows[i].RUMTP90 > comparison.Rows[j].RUMTP90
+		}
+		return comparison.Rows[i].Country < comparison.Rows[j].Country
+	})
+	return comparison
+}
+
+// CompareRUM retrieves the real user metrics of the given page and the
+// aggregate sample data of the given monitor for the same period and
+// compares them per country
+func CompareRUM(r *RealUserMeasurements, m *Monitoring, monitorID, page string, start, end time.Time, opts *RUMComparisonOptions) (RUMComparison, error) {
+	monitors, err := m.Get(monitorID)
+	if err != nil {
+		return RUMComparison{}, err
+	}
+	if len(monitors) == 0 {
+		return RUMComparison{}, errors.New("neustar: monitor not found")
+	}
+
+	startDate := start.UTC().Format(historyDateFormat)
+	endDate := end.UTC().Format(historyDateFormat)
+	rum, err := r.AllMetrics(&RUMQuery{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Interval:  "day",
+		GroupBy:   "country",
+		Page:      page,
+	})
+	if err != nil {
+		return RUMComparison{}, err
+	}
+	synthetic, err := m.AggregateSampleData(monitorID, &AggregateSampleParameters{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Frequency: "day",
+		GroupBy:   "location",
+	})
+	if err != nil {
+		return RUMComparison{}, err
+	}
+
+	comparison := NewRUMComparison(page, monitors[0].Locations, rum, synthetic, opts)
+	comparison.StartDate = startDate
+	comparison.EndDate = endDate
+	return comparison, nil
+}
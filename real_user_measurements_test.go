@@ -0,0 +1,138 @@
+package neustar
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestNewRealUserMeasurements
+func TestNewRealUserMeasurements(t *testing.T) {
+	t.Parallel()
+
+	r := NewRealUserMeasurements(setUp())
+
+	if reflect.TypeOf(r).String() != "*neustar.RealUserMeasurements" {
+		t.Error("Incorrect data type pointer returned from NewRealUserMeasurements function")
+	}
+}
+
+// TestRUMQueryValidate
+func TestRUMQueryValidate(t *testing.T) {
+	t.Parallel()
+
+	q := &RUMQuery{StartDate: "2015-10-01", EndDate: "2015-10-02", Interval: "hour", GroupBy: "country"}
+	if err := q.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	for name, q := range map[string]*RUMQuery{
+		"no dates":     {Interval: "hour"},
+		"bad interval": {StartDate: "2015-10-01", EndDate: "2015-10-02", Interval: "week"},
+		"bad groupBy":  {StartDate: "2015-10-01", EndDate: "2015-10-02", GroupBy: "city"},
+	} {
+		if err := q.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+// TestMetricsNilQuery
+func TestMetricsNilQuery(t *testing.T) {
+	t.Parallel()
+
+	r := NewRealUserMeasurements(setUp())
+	if _, _, err := r.Metrics(nil); err != errRUMQueryRequired {
+		t.Errorf("Metrics: expected errRUMQueryRequired, got %v", err)
+	}
+	if _, err := r.AllMetrics(nil); err != errRUMQueryRequired {
+		t.Errorf("AllMetrics: expected errRUMQueryRequired, got %v", err)
+	}
+}
+
+// TestDecodeRUMResponse
+func TestDecodeRUMResponse(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	w.WriteHeader(429)
+	w.WriteString(`{"error": {"code": "RUM_000", "message": "Request has been throttled"}}`)
+	_, err := decodeRUMResponse(w.Result())
+	if !errors.Is(err, ErrRUMThrottled) || errors.Is(err, ErrRUMForbidden) {
+		t.Errorf("expected a throttled error, got %v", err)
+	}
+	if !errors.Is(err, &RUMError{ReturnedAPIError: ReturnedAPIError{Code: "RUM_000"}}) {
+		t.Errorf("expected any RUMError with the same code to match, got %v", err)
+	}
+	var rumErr *RUMError
+	if !errors.As(err, &rumErr) || rumErr.StatusCode != 429 || !rumErr.Temporary() {
+		t.Errorf("expected a temporary *RUMError with status 429, got %#v", err)
+	}
+
+	w = httptest.NewRecorder()
+	w.WriteString(`{"data": {"more": true, "items": [{"time": "2015-10-01T10:00:00Z", "group": "US", "pageViews": 120, "tp50": 1800}]}}`)
+	data, err := decodeRUMResponse(w.Result())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.Data.More || len(data.Data.Items) != 1 || data.Data.Items[0].Group != "US" || data.Data.Items[0].TP50 != 1800 {
+		t.Errorf("unexpected data %+v", data.Data)
+	}
+}
+
+// TestLocationCoordinates
+func TestLocationCoordinates(t *testing.T) {
+	t.Parallel()
+
+	for _, location := range Locations {
+		if _, ok := LocationCoordinates[location]; !ok {
+			t.Errorf("no coordinates for location %s", location)
+		}
+	}
+
+	location, distance, ok := NearestLocation(CountryCoordinates["GB"], Locations)
+	if !ok || location != "london" || distance > 1 {
+		t.Errorf("expected london to be nearest to GB, got %s at %.0fkm", location, distance)
+	}
+	if _, _, ok := NearestLocation(GeoPoint{}, []string{"atlantis"}); ok {
+		t.Error("expected no nearest location without coordinates")
+	}
+}
+
+// TestNewRUMComparison
+func TestNewRUMComparison(t *testing.T) {
+	t.Parallel()
+
+	rum := []RUMMetric{
+		{Group: "US", PageViews: 100, TP50: 1000, TP90: 2000},
+		{Group: "US", PageViews: 300, TP50: 2000, TP90: 3000},
+		{Group: "IN", PageViews: 50, TP50: 4000, TP90: 9000},
+		{Group: "GB", PageViews: 80, TP50: 1500, TP90: 5000},
+	}
+	synthetic := []AggregateSampleResponse{
+		{Location: "dallas", Count: 10, TP50: 800, TP90: 1200},
+		{Location: "london", Count: 10, TP50: 900, TP90: 1400},
+	}
+	c := NewRUMComparison("https://example.com/", []string{"dallas", "london"}, rum, synthetic, nil)
+
+	if len(c.Rows) != 3 || c.Rows[0].Country != "IN" || c.Rows[2].Country != "US" {
+		t.Fatalf("expected rows sorted slowest first, got %+v", c.Rows)
+	}
+	us := c.Rows[2]
+	if us.PageViews != 400 || us.RUMTP50 != 1750 || us.RUMTP90 != 2750 {
+		t.Errorf("expected view weighted percentiles, got %+v", us)
+	}
+	if us.Location != "dallas" || !us.Covered || us.SyntheticTP90 != 1200 {
+		t.Errorf("expected US to be covered by dallas, got %+v", us)
+	}
+
+	gaps := c.Gaps()
+	if len(gaps) != 1 || gaps[0].Country != "IN" || gaps[0].NearestLocation != "mumbai" {
+		t.Errorf("expected IN to be the only gap, got %+v", gaps)
+	}
+	if !strings.Contains(c.String(), "! IN") {
+		t.Errorf("expected the gap to be marked, got:\n%s", c)
+	}
+}
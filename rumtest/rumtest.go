@@ -0,0 +1,344 @@
+// Package rumtest provides a local stand-in for the real user measurements
+// API. It accepts navigation timing beacons, keeps them in memory and answers
+// metrics queries in the same shape as the API, so code built on
+// neustar.RealUserMeasurements can be tested offline.
+//
+//	srv := rumtest.NewServer()
+//	defer srv.Close()
+//	srv.Add(rumtest.Beacon{Page: "/", Country: "US", Timing: timing})
+//	rum := srv.Client(neustar.NewNeustar("key", "secret"))
+//	metrics, err := rum.AllMetrics(&neustar.RUMQuery{...})
+package rumtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/briandowns/neustar"
+)
+
+// BeaconURI is the path beacons are posted to
+const BeaconURI = "/beacon"
+
+// DefaultPageSize is the number of metrics returned per page
+const DefaultPageSize = 2000
+
+// NavigationTiming holds the W3C navigation timing marks of a page view in
+// milliseconds since the epoch. Marks that were not reached are zero.
+type NavigationTiming struct {
+	NavigationStart          int64 `json:"navigationStart"`
+	DomainLookupStart        int64 `json:"domainLookupStart"`
+	DomainLookupEnd          int64 `json:"domainLookupEnd"`
+	ConnectStart             int64 `json:"connectStart"`
+	ConnectEnd               int64 `json:"connectEnd"`
+	RequestStart             int64 `json:"requestStart"`
+	ResponseStart            int64 `json:"responseStart"`
+	ResponseEnd              int64 `json:"responseEnd"`
+	DOMInteractive           int64 `json:"domInteractive"`
+	DOMContentLoadedEventEnd int64 `json:"domContentLoadedEventEnd"`
+	LoadEventEnd             int64 `json:"loadEventEnd"`
+}
+
+// Beacon holds a single page view as sent by a RUM tag
+type Beacon struct {
+	Page    string           `json:"page"`
+	Country string           `json:"country"`
+	Browser string           `json:"browser"`
+	Device  string           `json:"device"`
+	Timing  NavigationTiming `json:"timing"`
+}
+
+// Time returns when the page view started
+func (b Beacon) Time() time.Time {
+	return time.Unix(0, b.Timing.NavigationStart*int64(time.Millisecond)).UTC()
+}
+
+// LoadTime returns the page load time in milliseconds
+func (b Beacon) LoadTime() int {
+	return b.since(b.Timing.LoadEventEnd)
+}
+
+// since returns the milliseconds from the start of navigation to the given mark
+func (b Beacon) since(mark int64) int {
+	if mark == 0 {
+		return 0
+	}
+	return int(mark - b.Timing.NavigationStart)
+}
+
+// Server is a stand-in for the real user measurements API
+type Server struct {
+	// URL is the base URL of the server, without a trailing slash
+	URL string
+
+	// Key, when set, is the API key queries must carry
+	Key string
+
+	// PageSize is the number of metrics returned per page
+	PageSize int
+
+	mu      sync.Mutex
+	beacons []Beacon
+	server  *httptest.Server
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{PageSize: DefaultPageSize}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a RealUserMeasurements client using a copy of the given
+// credentials pointed at the server
+func (s *Server) Client(n *neustar.Neustar) *neustar.RealUserMeasurements {
+	c := *n
+	c.BaseURL = s.URL + "/"
+	return neustar.NewRealUserMeasurements(&c)
+}
+
+// Add stores the given beacons as if they had been posted
+func (s *Server) Add(beacons ...Beacon) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.beacons = append(s.beacons, beacons...)
+}
+
+// Beacons returns a copy of the stored beacons
+func (s *Server) Beacons() []Beacon {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	beacons := make([]Beacon, len(s.beacons))
+	copy(beacons, s.beacons)
+	return beacons
+}
+
+// Reset removes every stored beacon
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.beacons = nil
+}
+
+// ServeHTTP accepts beacons posted to BeaconURI and answers metrics queries
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case BeaconURI:
+		s.serveBeacon(w, r)
+	case "/" + neustar.RUMURI + neustar.MetricsURI:
+		s.serveMetrics(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveBeacon stores a posted beacon. Beacons sent with navigator.sendBeacon
+// arrive as text/plain, so the content type is not checked.
+func (s *Server) serveBeacon(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	var beacon Beacon
+	if err := json.NewDecoder(r.Body).Decode(&beacon); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if beacon.Page == "" || beacon.Timing.NavigationStart == 0 {
+		http.Error(w, "beacon requires a page and navigationStart", http.StatusBadRequest)
+		return
+	}
+	s.Add(beacon)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveMetrics answers a metrics query
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if s.Key != "" && q.Get("apikey") != s.Key {
+		writeError(w, http.StatusForbidden, "RUM_004", "apikey")
+		return
+	}
+	for _, param := range []string{"startDate", "endDate"} {
+		if q.Get(param) == "" {
+			writeError(w, http.StatusBadRequest, "RUM_005", param)
+			return
+		}
+	}
+	start, err := neustar.ParseTimestamp(q.Get("startDate"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "RUM_006", "startDate")
+		return
+	}
+	end, err := neustar.ParseTimestamp(q.Get("endDate"))
+	if err != nil || !end.After(start) {
+		writeError(w, http.StatusBadRequest, "RUM_006", "endDate")
+		return
+	}
+	interval := q.Get("interval")
+	if interval != "" && !neustar.ValidRUMInterval(interval) {
+		writeError(w, http.StatusBadRequest, "RUM_006", "interval")
+		return
+	}
+	groupBy := q.Get("groupBy")
+	if groupBy != "" && !neustar.ValidRUMGroupBy(groupBy) {
+		writeError(w, http.StatusBadRequest, "RUM_006", "groupBy")
+		return
+	}
+	offset := 0
+	if raw := q.Get("offset"); raw != "" {
+		if offset, err = strconv.Atoi(raw); err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, "RUM_006", "offset")
+			return
+		}
+	}
+
+	filter := func(b Beacon) bool {
+		t := b.Time()
+		return !t.Before(start) && t.Before(end) &&
+			match(q.Get("page"), b.Page) && match(q.Get("country"), b.Country) &&
+			match(q.Get("browser"), b.Browser) && match(q.Get("device"), b.Device)
+	}
+	metrics := aggregate(s.Beacons(), filter, start, interval, groupBy)
+
+	var data neustar.RUMDataResponse
+	data.Data.Total = len(metrics)
+	data.Data.Offset = offset
+	if offset < len(metrics) {
+		metrics = metrics[offset:]
+	} else {
+		metrics = nil
+	}
+	if s.PageSize > 0 && len(metrics) > s.PageSize {
+		metrics = metrics[:s.PageSize]
+		data.Data.More = true
+	}
+	data.Data.Items = metrics
+	writeJSON(w, http.StatusOK, data)
+}
+
+// aggregate groups the matching beacons into time buckets of the given
+// interval and by the given field, sorted by time and group
+func aggregate(beacons []Beacon, filter func(Beacon) bool, start time.Time, interval, groupBy string) []neustar.RUMMetric {
+	type key struct {
+		time  time.Time
+		group string
+	}
+	buckets := make(map[key][]Beacon)
+	for _, b := range beacons {
+		if !filter(b) {
+			continue
+		}
+		k := key{time: bucket(b.Time(), start, interval), group: group(b, groupBy)}
+		buckets[k] = append(buckets[k], b)
+	}
+
+	metrics := make([]neustar.RUMMetric, 0, len(buckets))
+	for k, views := range buckets {
+		loads := make([]int, len(views))
+		var load, ttfb, interactive int
+		for i, b := range views {
+			loads[i] = b.LoadTime()
+			load += loads[i]
+			ttfb += b.since(b.Timing.ResponseStart)
+			interactive += b.since(b.Timing.DOMInteractive)
+		}
+		sort.Ints(loads)
+		metrics = append(metrics, neustar.RUMMetric{
+			Time:              neustar.Timestamp{Time: k.time},
+			Group:             k.group,
+			PageViews:         len(views),
+			AvgLoadTime:       load / len(views),
+			TP50:              percentile(loads, 50),
+			TP75:              percentile(loads, 75),
+			TP90:              percentile(loads, 90),
+			TP95:              percentile(loads, 95),
+			AvgTTFB:           ttfb / len(views),
+			AvgDOMInteractive: interactive / len(views),
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if !metrics[i].Time.Equal(metrics[j].Time.Time) {
+			return metrics[i].Time.Before(metrics[j].Time.Time)
+		}
+		return metrics[i].Group < metrics[j].Group
+	})
+	return metrics
+}
+
+// bucket returns the start of the time bucket t falls in. Without an
+// interval the whole period is a single bucket.
+func bucket(t, start time.Time, interval string) time.Time {
+	switch interval {
+	case "minute":
+		return t.Truncate(time.Minute)
+	case "hour":
+		return t.Truncate(time.Hour)
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return start.UTC()
+}
+
+// group returns the value of the given groupBy field of the beacon
+func group(b Beacon, groupBy string) string {
+	switch groupBy {
+	case "page":
+		return b.Page
+	case "country":
+		return b.Country
+	case "browser":
+		return b.Browser
+	case "device":
+		return b.Device
+	}
+	return ""
+}
+
+// percentile returns the nearest rank percentile of the given sorted values
+func percentile(sorted []int, p int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// match returns true if the filter is empty or equal to the value
+func match(filter, value string) bool {
+	return filter == "" || filter == value
+}
+
+// writeError writes an error in the shape returned by the API
+func writeError(w http.ResponseWriter, status int, code, param string) {
+	writeJSON(w, status, map[string]neustar.ReturnedAPIError{
+		"error": {
+			Code:    code,
+			Message: neustar.RealUserMeasurementsErrorCodes[code],
+			Param:   param,
+		},
+	})
+}
+
+// writeJSON writes the given value as JSON with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,116 @@
+package rumtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/briandowns/neustar"
+)
+
+// beacon returns a beacon for the given page view starting at the given time
+func beacon(page, country string, start time.Time, load int64) Beacon {
+	ms := start.UnixNano() / int64(time.Millisecond)
+	return Beacon{
+		Page:    page,
+		Country: country,
+		Browser: "chrome",
+		Device:  "desktop",
+		Timing: NavigationTiming{
+			NavigationStart: ms,
+			ResponseStart:   ms + 100,
+			DOMInteractive:  ms + load/2,
+			LoadEventEnd:    ms + load,
+		},
+	}
+}
+
+// TestServerBeacon
+func TestServerBeacon(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	start := time.Date(2015, 10, 1, 10, 0, 0, 0, time.UTC)
+	body, _ := json.Marshal(beacon("/", "US", start, 1200))
+	response, err := http.Post(srv.URL+BeaconURI, "text/plain", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", response.StatusCode)
+	}
+
+	response, err = http.Post(srv.URL+BeaconURI, "application/json", bytes.NewReader([]byte(`{"page": "/"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a beacon without timing, got %d", response.StatusCode)
+	}
+
+	beacons := srv.Beacons()
+	if len(beacons) != 1 || beacons[0].LoadTime() != 1200 || !beacons[0].Time().Equal(start) {
+		t.Errorf("unexpected beacons %+v", beacons)
+	}
+}
+
+// TestServerMetrics
+func TestServerMetrics(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer()
+	defer srv.Close()
+	srv.PageSize = 1
+
+	day := time.Date(2015, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, load := range []int64{1000, 2000, 3000, 4000} {
+		srv.Add(beacon("/", "US", day.Add(time.Duration(i)*time.Minute), load))
+	}
+	srv.Add(
+		beacon("/", "IN", day.Add(time.Hour), 8000),
+		beacon("/checkout", "US", day, 500),
+		beacon("/", "US", day.Add(48*time.Hour), 9000),
+	)
+
+	rum := srv.Client(neustar.NewNeustar("key", "secret"))
+	metrics, err := rum.AllMetrics(&neustar.RUMQuery{
+		StartDate: "2015-10-01",
+		EndDate:   "2015-10-02",
+		Interval:  "day",
+		GroupBy:   "country",
+		Page:      "/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("expected a metric per country, got %+v", metrics)
+	}
+	in, us := metrics[0], metrics[1]
+	if in.Group != "IN" || in.PageViews != 1 || in.TP90 != 8000 {
+		t.Errorf("unexpected IN metric %+v", in)
+	}
+	if us.Group != "US" || us.PageViews != 4 || us.AvgLoadTime != 2500 || us.TP50 != 2000 || us.TP90 != 4000 ||
+		us.AvgTTFB != 100 || us.AvgDOMInteractive != 1250 || !us.Time.Equal(day) {
+		t.Errorf("unexpected US metric %+v", us)
+	}
+
+	srv.Key = "other"
+	_, _, err = rum.Metrics(&neustar.RUMQuery{StartDate: "2015-10-01", EndDate: "2015-10-02"})
+	if !errors.Is(err, neustar.ErrRUMForbidden) {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+	srv.Key = ""
+
+	_, _, err = rum.Metrics(&neustar.RUMQuery{StartDate: "2015-10-02", EndDate: "2015-10-01"})
+	if !errors.Is(err, neustar.ErrRUMInvalidParameter) {
+		t.Errorf("expected an invalid parameter error, got %v", err)
+	}
+}
@@ -1,16 +1,25 @@
 package neustar
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-querystring/query"
 )
 
 const (
 	// ScriptURI is the endpoint for calls to the scripting API
 	ScriptURI = "script/1.0"
 
-	// PolicyURI is the endpoint for policy calls
+	// CreateURI is the endpoint for quick creating a script from a URL
 	CreateURI = "/url"
 
 	// AllScripts is the endpoint for AllScripts calls
@@ -22,10 +31,25 @@ const (
 	// InvalidScriptsURI is the endpiont for ValidScripts calls
 	InvalidScriptsURI = "/InvalidScripts"
 
-	// UploadBodyURI is the endpiont for ValidScripts calls
+	// UploadBodyURI is the endpoint for uploading a script body
 	UploadBodyURI = "/upload/body"
+
+	// UploadFileURI is the endpoint for uploading a script file
+	UploadFileURI = "/upload/file"
+
+	// CloneURI is the endpoint for cloning a script
+	CloneURI = "/clone"
+
+	// VersionURI is the endpoint for script version calls
+	VersionURI = "/version"
 )
 
+// errScriptIDRequired is returned by calls made without a script ID
+var errScriptIDRequired = errors.New("neustar: script ID is required")
+
+// errScriptParametersRequired is returned by calls made without parameters
+var errScriptParametersRequired = errors.New("neustar: script parameters are required")
+
 // Script holds a representation of a script
 type Script struct {
 	ID      string `json:"id"`
@@ -34,59 +58,119 @@ type Script struct {
 }
 
 // ScriptingListResponse holds the response from the list endpoint
-type ScriptingListResponse struct {
-	// The name of the alert policy
-	Name string `json:"name"`
+//
+// Deprecated: use ScriptDetail.
+type ScriptingListResponse = ScriptDetail
 
-	// The ID of the alert policy
+// ScriptDetail holds the details of a script
+type ScriptDetail struct {
+	// The ID of the script
 	ID string `json:"id"`
 
-	// The description of the alert policy
+	// The name of the script
+	Name string `json:"name"`
+
+	// The description of the script
 	Description string `json:"description"`
 
-	// This flag will return true if the policy is an Advanced Alert Policy
-	AdvancedEdit bool `json:"advancedEdit"`
-
-	// The email addresses associated with the alert policy
-	EmailAddress []string `json:"emailAddresses"`
-
-	// The number of strikes before triggering an alert.
-	Strikes int `json:"strikes"`
-
-	ScriptInput          interface{} `json:"scriptInput"`
-	EscalationPolicy     interface{} `json:"escalationPolicy"`
-	FeedIDs              interface{} `json:"feedIds"`
-	PagerDutyServiceKey  interface{} `json:"pagerDutyServiceKey"`
-	PagerDutyConnectName interface{} `json:"pagerDutyConnectionName"`
-	PagerDutyAccount     interface{} `json:"pagerDutyAccount"`
-	SMS                  interface{} `json:"sms"`
-	PolicyGroup          interface{} `json:"policyGroup"`
-	History              interface{} `json:"history"`
-	AccountID            string      `json:"accountId"`
-	AlertType            interface{} `json:"alertType"`
-	ScriptBody           string      `json:"scriptBody"`
-	LastUser             string      `json:"lastUser"`
-	Created              string      `json:"created"`
-	InUse                bool        `json:"inUse"`
-	Modified             string      `json:"modified"`
-	LatestVersion        string      `json:"latestVersion"`
+	// The ID of the account the script belongs to
+	AccountID string `json:"accountId"`
+
+	// The JavaScript body of the script
+	ScriptBody string `json:"scriptBody"`
+
+	// The user who last modified the script
+	LastUser string `json:"lastUser"`
+
+	// When the script was created
+	Created Timestamp `json:"created"`
+
+	// When the script was last modified
+	Modified Timestamp `json:"modified"`
+
+	// Whether the script is used by any monitors
+	InUse bool `json:"inUse"`
+
+	// The version of the script that was returned
+	Version string `json:"version"`
+
+	// The latest version of the script
+	LatestVersion string `json:"latestVersion"`
+
+	// The version history of the script
+	Versions []ScriptVersion `json:"versions,omitempty"`
+
+	// The monitors that run the script
+	Monitors []ScriptMonitor `json:"monitors,omitempty"`
+
+	// The reasons the script failed validation. Only populated for
+	// invalid scripts.
+	ValidationErrors []ScriptValidationError `json:"validationErrors,omitempty"`
+}
+
+// ScriptVersion holds a single entry of a script's version history
+type ScriptVersion struct {
+	// The version number
+	Version string `json:"version"`
+
+	// The user who uploaded the version
+	LastUser string `json:"lastUser"`
+
+	// When the version was uploaded
+	Modified Timestamp `json:"modified"`
+}
+
+// ScriptMonitor holds a monitor that runs a script
+type ScriptMonitor struct {
+	// The ID of the monitor
+	ID string `json:"id"`
+
+	// The name of the monitor
+	Name string `json:"name"`
+
+	// The version of the script the monitor runs
+	ScriptVersion string `json:"scriptVersion"`
+}
+
+// ScriptValidationError holds a single reason a script failed validation
+type ScriptValidationError struct {
+	// The line of the script the error was found on
+	LineNumber int `json:"lineNumber"`
+
+	// A description of the error
+	Message string `json:"message"`
+}
+
+// ScriptListParameters holds the allowed options for listing scripts
+type ScriptListParameters struct {
+	// From which position in the return list you wish to start
+	Offset int `url:"offset"`
 }
 
 // ScriptCreateParameters holds the parameters passed in to
 // create a new script
 type ScriptCreateParameters struct {
-	// The name of the alert policy
+	// The name of the script
 	Name string `json:"name"`
 
-	// An array of comma-separated email addresses
-	// associated with the alert policy e.g. ["alert@mycompany.com","myemail@gmail.com"].
-	EmailAddresses []string `json:"emailAddresses"`
+	// A description of what the script does
+	Description string `json:"description,omitempty"`
 
-	// The number of strikes before triggering an alert.
-	Strikes int `json:"strikes"`
+	// The JavaScript body of the script
+	Body string `json:"scriptBody"`
+}
 
-	// A description for the alert policy
-	Description string `json:"description"`
+// ScriptCreateFromURLParameters holds the parameters passed in to
+// quick create a script that simply opens the given URL
+type ScriptCreateFromURLParameters struct {
+	// The name of the script
+	Name string `json:"name"`
+
+	// A description of what the script does
+	Description string `json:"description,omitempty"`
+
+	// The URL the generated script will open
+	URL string `json:"url"`
 }
 
 // Scripting holds scripting config
@@ -104,39 +188,330 @@ func NewScript(neustar *Neustar) *Scripting {
 // ScriptDataResponse holds the return from the API list call
 type ScriptDataResponse struct {
 	Data struct {
-		Total  int                     `json:"total"`
-		Offset int                     `json:"offset"`
-		More   bool                    `json:"more"`
-		Items  []ScriptingListResponse `json:"items"`
+		Total  int            `json:"total"`
+		Offset int            `json:"offset"`
+		More   bool           `json:"more"`
+		Items  []ScriptDetail `json:"items"`
 	} `json:"data"`
 }
 
-// Create creates a new Alert policy
-func (s *Scripting) Create() {}
+// Create creates a new script from the given name, description and body and
+// returns the ID and version of the newly created script.
+func (s *Scripting) Create(scp *ScriptCreateParameters) (Script, error) {
+	if scp == nil {
+		return Script{}, errScriptParametersRequired
+	}
+	script, err := s.create("", scp)
+	if err != nil {
+		return Script{}, err
+	}
+	if script.Name == "" {
+		script.Name = scp.Name
+	}
+	return script, nil
+}
+
+// CreateFromURL quick creates a new script that opens the given URL and
+// returns the ID and version of the newly created script.
+func (s *Scripting) CreateFromURL(scp *ScriptCreateFromURLParameters) (Script, error) {
+	if scp == nil {
+		return Script{}, errScriptParametersRequired
+	}
+	script, err := s.create(CreateURI, scp)
+	if err != nil {
+		return Script{}, err
+	}
+	if script.Name == "" {
+		script.Name = scp.Name
+	}
+	return script, nil
+}
+
+// UploadBody uploads the given script body as a new script and returns the
+// ID and version of the newly created script.
+func (s *Scripting) UploadBody(scp *ScriptCreateParameters) (Script, error) {
+	if scp == nil {
+		return Script{}, errScriptParametersRequired
+	}
+	script, err := s.create(UploadBodyURI, scp)
+	if err != nil {
+		return Script{}, err
+	}
+	if script.Name == "" {
+		script.Name = scp.Name
+	}
+	return script, nil
+}
 
-// List retrieves a list of policies ordered by date in descending order.
-func (s *Scripting) List() ([]ScriptingListResponse, int, error) {
+// create posts the given parameters to the given script endpoint
+func (s *Scripting) create(uri string, params interface{}) (Script, error) {
+	buffer, err := json.Marshal(params)
+	if err != nil {
+		return Script{}, err
+	}
+	body := bytes.NewBuffer(buffer)
+	request, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s%s%s?apikey=%s&sig=%s", s.neustar.baseURL(), ScriptURI, uri, s.neustar.Key, s.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		return Script{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return Script{}, err
+	}
+	defer response.Body.Close()
+	return decodeScript(response)
+}
+
+// decodeScript decodes the script returned in the given response, failing on
+// error statuses and on responses without a script ID
+func decodeScript(response *http.Response) (Script, error) {
+	if err := checkResponse(response); err != nil {
+		return Script{}, err
+	}
+	var data map[string]map[string]Script
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return Script{}, err
+	}
+	script := data["data"]["items"]
+	if script.ID == "" {
+		return Script{}, errors.New("neustar: API returned no script ID")
+	}
+	return script, nil
+}
+
+// List retrieves a list of scripts ordered by date in descending order.
+func (s *Scripting) List() ([]ScriptDetail, int, error) {
 	var response *http.Response
 	var data ScriptDataResponse
-	response, err := http.Get(fmt.Sprintf("%s%s?apikey=%s&sig=%s", BaseURL, ScriptURI, s.neustar.Key, s.neustar.DigitalSignature()))
+	response, err := http.Get(fmt.Sprintf("%s%s?apikey=%s&sig=%s", s.neustar.baseURL(), ScriptURI, s.neustar.Key, s.neustar.DigitalSignature()))
 	if err != nil {
-		return nil, response.StatusCode, err
+		return nil, 0, err
 	}
 	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, response.StatusCode, err
+	}
 	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
 		return nil, response.StatusCode, err
 	}
 	return data.Data.Items, response.StatusCode, nil
 }
 
-// ListValidTestScripts retrieves a list of valid test scripts
-func (s *Scripting) ListValidTestScripts() {}
+// Get retrieves the latest version of the script with the given ID, including
+// its body, version history and the monitors that run it.
+func (s *Scripting) Get(id string) (ScriptDetail, error) {
+	if id == "" {
+		return ScriptDetail{}, errScriptIDRequired
+	}
+	return s.get(fmt.Sprintf("/%s", id))
+}
+
+// GetVersion retrieves the given version of the script with the given ID
+func (s *Scripting) GetVersion(id, version string) (ScriptDetail, error) {
+	if id == "" {
+		return ScriptDetail{}, errScriptIDRequired
+	}
+	return s.get(fmt.Sprintf("/%s%s/%s", id, VersionURI, version))
+}
+
+// get retrieves a single script from the given endpoint
+func (s *Scripting) get(uri string) (ScriptDetail, error) {
+	var response *http.Response
+	var data map[string]map[string]ScriptDetail
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s%s?apikey=%s&sig=%s",
+		s.neustar.baseURL(), ScriptURI, uri, s.neustar.Key, s.neustar.DigitalSignature()))
+	if err != nil {
+		return ScriptDetail{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return ScriptDetail{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return ScriptDetail{}, err
+	}
+	return data["data"]["items"], nil
+}
+
+// ListAllTestScripts retrieves a page of all test scripts. If the 'more'
+// field is set to true, make another call with the offset set to the number
+// of results returned so far.
+func (s *Scripting) ListAllTestScripts(slp *ScriptListParameters) (ScriptDataResponse, error) {
+	return s.listScripts(AllScriptsURI, slp)
+}
 
-// ListInvalidTestScripts retrieves a list of invalid test scripts
-func (s *Scripting) ListInvalidTestScripts() {}
+// ListValidTestScripts retrieves a page of valid test scripts. If the 'more'
+// field is set to true, make another call with the offset set to the number
+// of results returned so far.
+func (s *Scripting) ListValidTestScripts(slp *ScriptListParameters) (ScriptDataResponse, error) {
+	return s.listScripts(ValidSciptsURI, slp)
+}
 
-// UploadTestScriptFile
-func (s *Scripting) UploadTestScriptFile() {}
+// ListInvalidTestScripts retrieves a page of invalid test scripts. If the 'more'
+// field is set to true, make another call with the offset set to the number
+// of results returned so far.
+func (s *Scripting) ListInvalidTestScripts(slp *ScriptListParameters) (ScriptDataResponse, error) {
+	return s.listScripts(InvalidScriptsURI, slp)
+}
 
-// CloneTestScriptFile
-func (s *Scripting) CloneTestScriptFile() {}
+// listScripts retrieves a page of scripts from the given endpoint
+func (s *Scripting) listScripts(uri string, slp *ScriptListParameters) (ScriptDataResponse, error) {
+	if slp == nil {
+		slp = &ScriptListParameters{}
+	}
+	v, err := query.Values(slp)
+	if err != nil {
+		return ScriptDataResponse{}, err
+	}
+	var data ScriptDataResponse
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s%s?%s&apikey=%s&sig=%s",
+		s.neustar.baseURL(), ScriptURI, uri, v.Encode(), s.neustar.Key, s.neustar.DigitalSignature()))
+	if err != nil {
+		return ScriptDataResponse{}, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return ScriptDataResponse{}, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return ScriptDataResponse{}, err
+	}
+	return data, nil
+}
+
+// InvalidScript holds an invalid script and the reasons it failed validation
+type InvalidScript struct {
+	ID       string
+	Name     string
+	Version  string
+	LastUser string
+	Modified Timestamp
+	Errors   []ScriptValidationError
+}
+
+// ScriptValidationReport holds all invalid scripts for an account
+type ScriptValidationReport struct {
+	Scripts []InvalidScript
+}
+
+// String renders the report with one line per validation error
+func (r ScriptValidationReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d invalid script(s)\n", len(r.Scripts))
+	for _, script := range r.Scripts {
+		fmt.Fprintf(&b, "%s (%s) version %s, last modified %s by %s\n",
+			script.Name, script.ID, script.Version, script.Modified.Format("2006-01-02 15:04"), script.LastUser)
+		if len(script.Errors) == 0 {
+			b.WriteString("\tno reason given\n")
+		}
+		for _, e := range script.Errors {
+			fmt.Fprintf(&b, "\tline %d: %s\n", e.LineNumber, e.Message)
+		}
+	}
+	return b.String()
+}
+
+// NewScriptValidationReport builds a validation report from the given scripts
+func NewScriptValidationReport(scripts []ScriptDetail) ScriptValidationReport {
+	var report ScriptValidationReport
+	for _, script := range scripts {
+		report.Scripts = append(report.Scripts, InvalidScript{
+			ID:       script.ID,
+			Name:     script.Name,
+			Version:  script.LatestVersion,
+			LastUser: script.LastUser,
+			Modified: script.Modified,
+			Errors:   script.ValidationErrors,
+		})
+	}
+	return report
+}
+
+// InvalidScriptsReport pages through all invalid test scripts and returns a
+// report explaining why each one failed validation.
+func (s *Scripting) InvalidScriptsReport() (ScriptValidationReport, error) {
+	var scripts []ScriptDetail
+	err := pageAll(func(offset int) (int, bool, error) {
+		data, err := s.ListInvalidTestScripts(&ScriptListParameters{Offset: offset})
+		if err != nil {
+			return 0, false, err
+		}
+		scripts = append(scripts, data.Data.Items...)
+		return len(data.Data.Items), data.Data.More, nil
+	})
+	if err != nil {
+		return ScriptValidationReport{}, err
+	}
+	return NewScriptValidationReport(scripts), nil
+}
+
+// UploadTestScriptFile streams the script read from r to the API as a multipart
+// file upload and returns the ID and version of the newly created script. The
+// name is used as both the script name and the uploaded file name.
+func (s *Scripting) UploadTestScriptFile(name string, r io.Reader) (Script, error) {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		part, err := form.CreateFormFile("file", name)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(part, r); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if err := form.WriteField("name", strings.TrimSuffix(name, filepath.Ext(name))); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(form.Close())
+	}()
+	request, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf("%s%s%s?apikey=%s&sig=%s", s.neustar.baseURL(), ScriptURI, UploadFileURI, s.neustar.Key, s.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		body.Close()
+		return Script{}, err
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return Script{}, err
+	}
+	defer response.Body.Close()
+	return decodeScript(response)
+}
+
+// UploadTestScriptFileFromPath uploads the .js script found at the given path
+// and returns the ID and version of the newly created script.
+func (s *Scripting) UploadTestScriptFileFromPath(path string) (Script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Script{}, err
+	}
+	defer f.Close()
+	return s.UploadTestScriptFile(filepath.Base(path), f)
+}
+
+// CloneTestScriptFile duplicates the script with the given ID under a new name
+// and returns the ID and version of the newly created script.
+func (s *Scripting) CloneTestScriptFile(id, newName string) (Script, error) {
+	script, err := s.create(fmt.Sprintf("/%s%s", id, CloneURI), map[string]string{"name": newName})
+	if err != nil {
+		return Script{}, err
+	}
+	if script.Name == "" {
+		script.Name = newName
+	}
+	return script, nil
+}
@@ -0,0 +1,172 @@
+package neustar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultWaitTimeout is used by WaitForText when no timeout is given
+const defaultWaitTimeout = 30 * time.Second
+
+// ScriptBuilder composes the steps of a monitoring script and renders them
+// as a script body that can be passed to Scripting.Create. Methods can be
+// chained and the first error encountered is returned by Build.
+type ScriptBuilder struct {
+	lines  []string
+	inStep bool
+	err    error
+}
+
+// NewScriptBuilder creates a new ScriptBuilder object
+func NewScriptBuilder() *ScriptBuilder {
+	return &ScriptBuilder{}
+}
+
+// BeginStep starts a new named step. Any step still open is ended first.
+func (b *ScriptBuilder) BeginStep(name string) *ScriptBuilder {
+	if name == "" {
+		return b.fail(errors.New("neustar: step name is required"))
+	}
+	if b.inStep {
+		b.EndStep()
+	}
+	b.inStep = true
+	return b.add("test.beginStep(%s);", jsString(name))
+}
+
+// EndStep ends the current step
+func (b *ScriptBuilder) EndStep() *ScriptBuilder {
+	if !b.inStep {
+		return b.fail(errors.New("neustar: EndStep called without BeginStep"))
+	}
+	b.inStep = false
+	return b.add("test.endStep();")
+}
+
+// Open loads the given URL in the browser
+func (b *ScriptBuilder) Open(rawURL string) *ScriptBuilder {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return b.fail(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return b.fail(fmt.Errorf("neustar: %s is not an http or https URL", rawURL))
+	}
+	return b.add("c.get(%s);", jsString(rawURL))
+}
+
+// Click clicks the element matching the given CSS selector
+func (b *ScriptBuilder) Click(selector string) *ScriptBuilder {
+	if selector == "" {
+		return b.fail(errors.New("neustar: selector is required"))
+	}
+	return b.add("c.findElement(By.cssSelector(%s)).click();", jsString(selector))
+}
+
+// Type enters the given text into the element matching the given CSS selector
+func (b *ScriptBuilder) Type(selector, text string) *ScriptBuilder {
+	if selector == "" {
+		return b.fail(errors.New("neustar: selector is required"))
+	}
+	return b.add("c.findElement(By.cssSelector(%s)).sendKeys(%s);", jsString(selector), jsString(text))
+}
+
+// WaitForText waits until the given text is present on the page. A timeout of
+// zero waits for 30 seconds.
+func (b *ScriptBuilder) WaitForText(text string, timeout time.Duration) *ScriptBuilder {
+	if text == "" {
+		return b.fail(errors.New("neustar: text is required"))
+	}
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+	return b.add("test.waitFor(function() { return c.getPageSource().indexOf(%s) != -1; }, %d);",
+		jsString(text), timeout/time.Millisecond)
+}
+
+// AssertStatus fails the script when the status code of the last page loaded
+// by Open is not the given code
+func (b *ScriptBuilder) AssertStatus(code int) *ScriptBuilder {
+	if code < 100 || code > 599 {
+		return b.fail(fmt.Errorf("neustar: %d is not a valid HTTP status code", code))
+	}
+	return b.add(
+		"if (c.getLastStatusCode() != %d) {\n\ttest.fail(\"expected status %d, got \" + c.getLastStatusCode());\n}",
+		code, code)
+}
+
+// Pause waits for the given duration
+func (b *ScriptBuilder) Pause(d time.Duration) *ScriptBuilder {
+	return b.add("test.pause(%d);", d/time.Millisecond)
+}
+
+// Build renders the script body. Any open step is ended and the result is
+// checked with ValidateScript before it is returned.
+func (b *ScriptBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if len(b.lines) == 0 {
+		return "", errors.New("neustar: script has no steps")
+	}
+
+	var body strings.Builder
+	body.WriteString("var c = test.openBrowser();\n")
+	body.WriteString("test.beginTransaction();\n")
+	for _, line := range b.lines {
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+	if b.inStep {
+		body.WriteString("test.endStep();\n")
+	}
+	body.WriteString("test.endTransaction();\n")
+
+	script := body.String()
+	if diagnostics := ValidateScript(script); diagnostics.HasErrors() {
+		return "", fmt.Errorf("neustar: generated script is invalid:\n%s", diagnostics)
+	}
+	return script, nil
+}
+
+// Parameters renders the script body and returns the parameters needed to
+// create it with Scripting.Create
+func (b *ScriptBuilder) Parameters(name, description string) (*ScriptCreateParameters, error) {
+	body, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &ScriptCreateParameters{
+		Name:        name,
+		Description: description,
+		Body:        body,
+	}, nil
+}
+
+func (b *ScriptBuilder) add(format string, args ...interface{}) *ScriptBuilder {
+	if b.err == nil {
+		b.lines = append(b.lines, fmt.Sprintf(format, args...))
+	}
+	return b
+}
+
+func (b *ScriptBuilder) fail(err error) *ScriptBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// jsString quotes the given string as a JavaScript string literal
+func jsString(s string) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
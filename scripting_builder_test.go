@@ -0,0 +1,64 @@
+package neustar
+
+import (
+	"testing"
+	"time"
+)
+
+// TestScriptBuilder
+func TestScriptBuilder(t *testing.T) {
+	t.Parallel()
+
+	body, err := NewScriptBuilder().
+		BeginStep("Home").
+		Open("https://example.com").
+		AssertStatus(200).
+		BeginStep("Search").
+		Type("#q", `say "hi"`).
+		Click("button[type=submit]").
+		WaitForText("Results", 10*time.Second).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `var c = test.openBrowser();
+test.beginTransaction();
+test.beginStep("Home");
+c.get("https://example.com");
+if (c.getLastStatusCode() != 200) {
+	test.fail("expected status 200, got " + c.getLastStatusCode());
+}
+test.endStep();
+test.beginStep("Search");
+c.findElement(By.cssSelector("#q")).sendKeys("say \"hi\"");
+c.findElement(By.cssSelector("button[type=submit]")).click();
+test.waitFor(function() { return c.getPageSource().indexOf("Results") != -1; }, 10000);
+test.endStep();
+test.endTransaction();
+`
+	if body != want {
+		t.Errorf("unexpected script, got:\n%s\nwant:\n%s", body, want)
+	}
+	if d := ValidateScript(body); len(d) != 0 {
+		t.Errorf("expected generated script to validate cleanly, got:\n%s", d)
+	}
+}
+
+// TestScriptBuilderErrors
+func TestScriptBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	builders := map[string]*ScriptBuilder{
+		"empty":         NewScriptBuilder(),
+		"bad url":       NewScriptBuilder().Open("ftp://example.com"),
+		"bad status":    NewScriptBuilder().Open("http://example.com").AssertStatus(42),
+		"stray endstep": NewScriptBuilder().EndStep(),
+	}
+
+	for name, b := range builders {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package neustar
+
+// ScriptDependency holds a script and the monitors that run it
+type ScriptDependency struct {
+	Script   ScriptDetail
+	Monitors []Monitor
+}
+
+// PinnedMonitor holds a monitor that runs an older version of its script
+type PinnedMonitor struct {
+	Monitor Monitor
+
+	// The version of the script the monitor runs
+	Version string
+
+	// The latest version of the script
+	LatestVersion string
+}
+
+// ScriptDependencyGraph holds the relationships between scripts and monitors
+type ScriptDependencyGraph struct {
+	// Every script along with the monitors that run it
+	Scripts []ScriptDependency
+
+	// Scripts that no monitor runs and can be deleted safely
+	Orphaned []ScriptDetail
+
+	// Monitors that run a version of their script older than the latest
+	Pinned []PinnedMonitor
+
+	// Monitors whose script is not in the list of scripts
+	Unresolved []Monitor
+}
+
+// NewScriptDependencyGraph builds the dependency graph from the given scripts
+// and monitors, as returned by Scripting.ListAllTestScripts and Monitoring.List
+func NewScriptDependencyGraph(scripts []ScriptDetail, monitors []Monitor) ScriptDependencyGraph {
+	var graph ScriptDependencyGraph
+	index := make(map[string]int, len(scripts))
+	for _, script := range scripts {
+		index[script.ID] = len(graph.Scripts)
+		graph.Scripts = append(graph.Scripts, ScriptDependency{Script: script})
+	}
+
+	for _, monitor := range monitors {
+		if monitor.Script.ID == "" {
+			continue
+		}
+		i, ok := index[monitor.Script.ID]
+		if !ok {
+			graph.Unresolved = append(graph.Unresolved, monitor)
+			continue
+		}
+		dependency := &graph.Scripts[i]
+		dependency.Monitors = append(dependency.Monitors, monitor)
+		latest := dependency.Script.LatestVersion
+		if monitor.Script.Version != "" && latest != "" && monitor.Script.Version != latest {
+			graph.Pinned = append(graph.Pinned, PinnedMonitor{
+				Monitor:       monitor,
+				Version:       monitor.Script.Version,
+				LatestVersion: latest,
+			})
+		}
+	}
+
+	for _, dependency := range graph.Scripts {
+		if len(dependency.Monitors) == 0 {
+			graph.Orphaned = append(graph.Orphaned, dependency.Script)
+		}
+	}
+	return graph
+}
+
+// MonitorsFor returns the monitors that run the script with the given ID
+func (g ScriptDependencyGraph) MonitorsFor(scriptID string) []Monitor {
+	for _, dependency := range g.Scripts {
+		if dependency.Script.ID == scriptID {
+			return dependency.Monitors
+		}
+	}
+	return nil
+}
+
+// CanDelete returns true if no monitor runs the script with the given ID
+func (g ScriptDependencyGraph) CanDelete(scriptID string) bool {
+	return len(g.MonitorsFor(scriptID)) == 0
+}
+
+// ScriptDependencies pages through all scripts, retrieves all monitors for
+// the account and builds their dependency graph
+func ScriptDependencies(s *Scripting, m *Monitoring) (ScriptDependencyGraph, error) {
+	var scripts []ScriptDetail
+	err := pageAll(func(offset int) (int, bool, error) {
+		data, err := s.ListAllTestScripts(&ScriptListParameters{Offset: offset})
+		if err != nil {
+			return 0, false, err
+		}
+		scripts = append(scripts, data.Data.Items...)
+		return len(data.Data.Items), data.Data.More, nil
+	})
+	if err != nil {
+		return ScriptDependencyGraph{}, err
+	}
+	monitors, err := m.List()
+	if err != nil {
+		return ScriptDependencyGraph{}, err
+	}
+	return NewScriptDependencyGraph(scripts, monitors), nil
+}
@@ -0,0 +1,71 @@
+package neustar
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestNewScriptDependencyGraph
+func TestNewScriptDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	scripts := []ScriptDetail{
+		{ID: "s1", Name: "home", LatestVersion: "3"},
+		{ID: "s2", Name: "checkout", LatestVersion: "1"},
+		{ID: "s3", Name: "unused", LatestVersion: "2"},
+	}
+	monitors := []Monitor{
+		{ID: "m1", Script: Script{ID: "s1", Version: "3"}},
+		{ID: "m2", Script: Script{ID: "s1", Version: "2"}},
+		{ID: "m3", Script: Script{ID: "s2", Version: "1"}},
+		{ID: "m4", Script: Script{ID: "gone", Version: "1"}},
+		{ID: "m5", Type: "dns"},
+	}
+
+	graph := NewScriptDependencyGraph(scripts, monitors)
+
+	if n := len(graph.MonitorsFor("s1")); n != 2 {
+		t.Errorf("expected 2 monitors for s1, got %d", n)
+	}
+	if len(graph.Orphaned) != 1 || graph.Orphaned[0].ID != "s3" {
+		t.Errorf("expected s3 to be the only orphaned script, got %+v", graph.Orphaned)
+	}
+	if len(graph.Pinned) != 1 || graph.Pinned[0].Monitor.ID != "m2" || graph.Pinned[0].LatestVersion != "3" {
+		t.Errorf("expected m2 to be pinned to an old version, got %+v", graph.Pinned)
+	}
+	if len(graph.Unresolved) != 1 || graph.Unresolved[0].ID != "m4" {
+		t.Errorf("expected m4 to be unresolved, got %+v", graph.Unresolved)
+	}
+	if graph.CanDelete("s1") || !graph.CanDelete("s3") {
+		t.Error("expected only s3 to be deletable")
+	}
+}
+
+// TestScriptDependencies
+func TestScriptDependencies(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/script/1.0/AllScripts" && r.URL.Query().Get("offset") == "0":
+			w.Write([]byte(`{"data": {"more": true, "items": [{"id": "s1"}, {"id": "s2"}]}}`))
+		case r.URL.Path == "/script/1.0/AllScripts" && r.URL.Query().Get("offset") == "2":
+			w.Write([]byte(`{"data": {"more": false, "items": [{"id": "s3"}]}}`))
+		case r.URL.Path == "/monitor/1.0":
+			w.Write([]byte(`{"data": {"items": [{"id": "m1", "script": {"id": "s3"}}]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	graph, err := ScriptDependencies(NewScript(n), NewMonitor(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Scripts) != 3 {
+		t.Errorf("expected 3 scripts across both pages, got %d", len(graph.Scripts))
+	}
+	if len(graph.Unresolved) != 0 || len(graph.MonitorsFor("s3")) != 1 {
+		t.Errorf("expected m1 to resolve to s3 from the second page, got %+v", graph)
+	}
+}
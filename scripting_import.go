@@ -0,0 +1,232 @@
+package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// skippedHeaders holds request headers that are set by the HTTP client and
+// should not be replayed
+var skippedHeaders = map[string]bool{
+	"host":            true,
+	"connection":      true,
+	"content-length":  true,
+	"cookie":          true,
+	"accept-encoding": true,
+}
+
+// staticExtensions holds the file extensions skipped when SkipStaticResources is set
+var staticExtensions = map[string]bool{
+	".css": true, ".js": true, ".png": true, ".jpg": true, ".jpeg": true,
+	".gif": true, ".svg": true, ".ico": true, ".woff": true, ".woff2": true,
+	".ttf": true, ".eot": true, ".webp": true, ".map": true,
+}
+
+// HARImportOptions holds the options for converting a HAR file into a script
+type HARImportOptions struct {
+	// Skip requests for stylesheets, scripts, images and fonts
+	SkipStaticResources bool
+
+	// Additional request headers that should not be replayed
+	ExcludeHeaders []string
+}
+
+// harLog holds the parts of a HAR file needed to build a script
+type harLog struct {
+	Log struct {
+		Pages []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"pages"`
+		Entries []struct {
+			Pageref string `json:"pageref"`
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// recording holds the parts of a Chrome DevTools Recorder export needed to
+// build a script
+type recording struct {
+	Title string `json:"title"`
+	Steps []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"steps"`
+}
+
+// virtualUserScript writes the statements of a VirtualUser script
+type virtualUserScript struct {
+	body   strings.Builder
+	inStep bool
+}
+
+func newVirtualUserScript() *virtualUserScript {
+	v := &virtualUserScript{}
+	v.body.WriteString("var c = test.openHttpClient();\n")
+	v.body.WriteString("var req;\n")
+	v.body.WriteString("test.beginTransaction();\n")
+	return v
+}
+
+func (v *virtualUserScript) beginStep(name string) {
+	v.endStep()
+	fmt.Fprintf(&v.body, "test.beginStep(%s);\n", jsString(name))
+	v.inStep = true
+}
+
+func (v *virtualUserScript) endStep() {
+	if v.inStep {
+		v.body.WriteString("test.endStep();\n")
+		v.inStep = false
+	}
+}
+
+func (v *virtualUserScript) request(method, rawURL string) {
+	switch strings.ToUpper(method) {
+	case "GET":
+		fmt.Fprintf(&v.body, "req = c.newGet(%s);\n", jsString(rawURL))
+	case "POST":
+		fmt.Fprintf(&v.body, "req = c.newPost(%s);\n", jsString(rawURL))
+	case "PUT":
+		fmt.Fprintf(&v.body, "req = c.newPut(%s);\n", jsString(rawURL))
+	case "DELETE":
+		fmt.Fprintf(&v.body, "req = c.newDelete(%s);\n", jsString(rawURL))
+	default:
+		fmt.Fprintf(&v.body, "req = c.newRequest(%s, %s);\n", jsString(strings.ToUpper(method)), jsString(rawURL))
+	}
+}
+
+func (v *virtualUserScript) build() (string, error) {
+	v.endStep()
+	v.body.WriteString("test.endTransaction();\n")
+	script := v.body.String()
+	if diagnostics := ValidateScript(script); diagnostics.HasErrors() {
+		return "", fmt.Errorf("neustar: generated script is invalid:\n%s", diagnostics)
+	}
+	return script, nil
+}
+
+// ImportHAR converts the HAR file read from r into a VirtualUser script body
+// with one step per page. Request headers and POST bodies are replayed.
+func ImportHAR(r io.Reader, opts *HARImportOptions) (string, error) {
+	if opts == nil {
+		opts = &HARImportOptions{}
+	}
+	excluded := make(map[string]bool, len(opts.ExcludeHeaders))
+	for _, header := range opts.ExcludeHeaders {
+		excluded[strings.ToLower(header)] = true
+	}
+
+	var har harLog
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return "", err
+	}
+	if len(har.Log.Entries) == 0 {
+		return "", errors.New("neustar: HAR file contains no entries")
+	}
+
+	titles := make(map[string]string, len(har.Log.Pages))
+	for _, page := range har.Log.Pages {
+		titles[page.ID] = page.Title
+		if page.Title == "" {
+			titles[page.ID] = page.ID
+		}
+	}
+
+	script := newVirtualUserScript()
+	currentPage := "\x00"
+	for _, entry := range har.Log.Entries {
+		request := entry.Request
+		if opts.SkipStaticResources && isStaticResource(request.URL) {
+			continue
+		}
+		if entry.Pageref != currentPage {
+			currentPage = entry.Pageref
+			name := titles[currentPage]
+			if name == "" {
+				name = stepNameFromURL(request.URL)
+			}
+			script.beginStep(name)
+		}
+
+		script.request(request.Method, request.URL)
+		for _, header := range request.Headers {
+			name := strings.ToLower(header.Name)
+			if strings.HasPrefix(name, ":") || skippedHeaders[name] || excluded[name] {
+				continue
+			}
+			fmt.Fprintf(&script.body, "req.addRequestHeader(%s, %s);\n", jsString(header.Name), jsString(header.Value))
+		}
+		if request.PostData != nil && request.PostData.Text != "" {
+			fmt.Fprintf(&script.body, "req.setRequestBody(%s, %s);\n",
+				jsString(request.PostData.Text), jsString(request.PostData.MimeType))
+		}
+		script.body.WriteString("req.execute();\n")
+	}
+	return script.build()
+}
+
+// ImportRecording converts the Chrome DevTools Recorder export read from r
+// into a VirtualUser script body with one step per navigation. Browser
+// interactions such as clicks cannot be replayed by a VirtualUser script and
+// are written as comments.
+func ImportRecording(r io.Reader) (string, error) {
+	var rec recording
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return "", err
+	}
+
+	script := newVirtualUserScript()
+	var navigations int
+	for _, step := range rec.Steps {
+		switch step.Type {
+		case "navigate":
+			script.beginStep(stepNameFromURL(step.URL))
+			script.request("GET", step.URL)
+			script.body.WriteString("req.execute();\n")
+			navigations++
+		case "setViewport":
+		default:
+			fmt.Fprintf(&script.body, "// skipped %s step\n", strings.Replace(step.Type, "\n", " ", -1))
+		}
+	}
+	if navigations == 0 {
+		return "", errors.New("neustar: recording contains no navigate steps")
+	}
+	return script.build()
+}
+
+// isStaticResource reports whether the given URL points at a static asset
+func isStaticResource(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return staticExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// stepNameFromURL names a step after the host and path of the given URL
+func stepNameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host + u.Path
+}
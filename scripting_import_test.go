@@ -0,0 +1,79 @@
+package neustar
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestImportHAR
+func TestImportHAR(t *testing.T) {
+	t.Parallel()
+
+	har := `{"log": {
+		"pages": [{"id": "page_1", "title": "Home"}, {"id": "page_2", "title": "Login"}],
+		"entries": [
+			{"pageref": "page_1", "request": {"method": "GET", "url": "https://example.com/",
+				"headers": [{"name": "Accept", "value": "text/html"}, {"name": "Cookie", "value": "a=b"}, {"name": ":authority", "value": "example.com"}]}},
+			{"pageref": "page_1", "request": {"method": "GET", "url": "https://example.com/app.css", "headers": []}},
+			{"pageref": "page_2", "request": {"method": "POST", "url": "https://example.com/login",
+				"headers": [{"name": "X-Trace", "value": "1"}],
+				"postData": {"mimeType": "application/x-www-form-urlencoded", "text": "user=a&pass=\"b\""}}}
+		]
+	}}`
+
+	body, err := ImportHAR(strings.NewReader(har), &HARImportOptions{
+		SkipStaticResources: true,
+		ExcludeHeaders:      []string{"x-trace"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `var c = test.openHttpClient();
+var req;
+test.beginTransaction();
+test.beginStep("Home");
+req = c.newGet("https://example.com/");
+req.addRequestHeader("Accept", "text/html");
+req.execute();
+test.endStep();
+test.beginStep("Login");
+req = c.newPost("https://example.com/login");
+req.setRequestBody("user=a&pass=\"b\"", "application/x-www-form-urlencoded");
+req.execute();
+test.endStep();
+test.endTransaction();
+`
+	if body != want {
+		t.Errorf("unexpected script, got:\n%s\nwant:\n%s", body, want)
+	}
+
+	if _, err := ImportHAR(strings.NewReader(`{"log": {"entries": []}}`), nil); err == nil {
+		t.Error("expected an error for a HAR file without entries")
+	}
+}
+
+// TestImportRecording
+func TestImportRecording(t *testing.T) {
+	t.Parallel()
+
+	rec := `{"title": "checkout", "steps": [
+		{"type": "setViewport"},
+		{"type": "navigate", "url": "https://example.com/cart"},
+		{"type": "click", "selectors": [["#buy"]]}
+	]}`
+
+	body, err := ImportRecording(strings.NewReader(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`test.beginStep("example.com/cart");`,
+		`req = c.newGet("https://example.com/cart");`,
+		"// skipped click step",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected script to contain %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package neustar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// SeverityError marks a diagnostic that will cause the script to fail
+	SeverityError = "error"
+
+	// SeverityWarning marks a diagnostic that is likely a mistake
+	SeverityWarning = "warning"
+)
+
+// ScriptAPIMethods is a slice of the methods available on the 'test' object
+// of a Neustar script. Calls to any other method are reported by ValidateScript.
+var ScriptAPIMethods = []string{
+	"openBrowser",
+	"openHttpClient",
+	"beginTransaction",
+	"endTransaction",
+	"beginStep",
+	"endStep",
+	"pause",
+	"log",
+	"fail",
+	"assertTrue",
+	"assertFalse",
+	"getParam",
+	"setUserAgent",
+	"setSimulatedBps",
+	"setStepTimeout",
+	"setTransactionTimeout",
+	"blacklistRequests",
+	"whitelistRequests",
+	"rewriteRequest",
+	"setHeader",
+	"removeHeader",
+	"waitForNetworkTrafficToStop",
+	"waitForResponse",
+	"waitFor",
+	"remapHost",
+	"setVirtualDns",
+	"enableVirtualDns",
+	"dnsFlush",
+}
+
+// ScriptDiagnostic holds a single problem found in a script
+type ScriptDiagnostic struct {
+	// The line the problem was found on, starting at 1
+	Line int
+
+	// The column the problem was found on, starting at 1
+	Column int
+
+	// Either SeverityError or SeverityWarning
+	Severity string
+
+	// A description of the problem
+	Message string
+}
+
+// String renders the diagnostic as line:column: severity: message
+func (d ScriptDiagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// ScriptDiagnostics holds all problems found in a script
+type ScriptDiagnostics []ScriptDiagnostic
+
+// HasErrors returns true if any of the diagnostics is an error
+func (d ScriptDiagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the diagnostics with one per line
+func (d ScriptDiagnostics) String() string {
+	var b strings.Builder
+	for _, diagnostic := range d {
+		b.WriteString(diagnostic.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ValidScriptAPIMethod verifies the given method exists on the 'test' object
+func ValidScriptAPIMethod(method string) bool {
+	for _, i := range ScriptAPIMethods {
+		if i == method {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateScript parses the given script body offline and reports syntax
+// errors, unbalanced brackets, calls to unknown 'test' methods and problems
+// with the transaction and step structure. The structure checks follow the
+// script top to bottom and do not evaluate branches or loops.
+func ValidateScript(body string) ScriptDiagnostics {
+	l := &scriptLexer{src: []rune(body), line: 1, col: 1}
+	l.run()
+	diagnostics := l.diagnostics
+	diagnostics = append(diagnostics, checkBrackets(l.tokens)...)
+	diagnostics = append(diagnostics, checkTestCalls(l.tokens)...)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
+
+// tokenKind identifies the type of a lexed token
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenRegexp
+	tokenPunct
+)
+
+// scriptToken holds a single lexed token
+type scriptToken struct {
+	kind tokenKind
+	text string
+	line int
+	col  int
+}
+
+// scriptLexer splits a script into tokens, recording syntax errors
+type scriptLexer struct {
+	src         []rune
+	pos         int
+	line        int
+	col         int
+	tokens      []scriptToken
+	diagnostics ScriptDiagnostics
+}
+
+// regexpKeywords holds the keywords after which a '/' starts a regular expression
+var regexpKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "in": true,
+	"of": true, "new": true, "delete": true, "void": true, "throw": true,
+}
+
+func (l *scriptLexer) peek(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *scriptLexer) next() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+func (l *scriptLexer) errorf(line, col int, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, ScriptDiagnostic{
+		Line:     line,
+		Column:   col,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *scriptLexer) emit(kind tokenKind, start, line, col int) {
+	l.tokens = append(l.tokens, scriptToken{
+		kind: kind,
+		text: string(l.src[start:l.pos]),
+		line: line,
+		col:  col,
+	})
+}
+
+// regexpAllowed reports whether a '/' at the current position starts a
+// regular expression rather than a division
+func (l *scriptLexer) regexpAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+	last := l.tokens[len(l.tokens)-1]
+	switch last.kind {
+	case tokenPunct:
+		return !strings.Contains(")]}", last.text)
+	case tokenIdent:
+		return regexpKeywords[last.text]
+	}
+	return false
+}
+
+func (l *scriptLexer) run() {
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		start, line, col := l.pos, l.line, l.col
+		switch {
+		case unicode.IsSpace(r):
+			l.next()
+		case r == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.next()
+			}
+		case r == '/' && l.peek(1) == '*':
+			l.next()
+			l.next()
+			for l.pos < len(l.src) && !(l.peek(0) == '*' && l.peek(1) == '/') {
+				l.next()
+			}
+			if l.pos >= len(l.src) {
+				l.errorf(line, col, "unterminated comment")
+				return
+			}
+			l.next()
+			l.next()
+		case r == '"' || r == '\'':
+			l.lexString(r, line, col)
+			l.emit(tokenString, start, line, col)
+		case r == '`':
+			l.lexTemplate(line, col)
+			l.emit(tokenString, start, line, col)
+		case r == '/' && l.regexpAllowed():
+			l.lexRegexp(line, col)
+			l.emit(tokenRegexp, start, line, col)
+		case unicode.IsDigit(r):
+			for l.pos < len(l.src) && (unicode.IsLetter(l.peek(0)) || unicode.IsDigit(l.peek(0)) || l.peek(0) == '.') {
+				l.next()
+			}
+			l.emit(tokenNumber, start, line, col)
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			for l.pos < len(l.src) && (unicode.IsLetter(l.peek(0)) || unicode.IsDigit(l.peek(0)) || l.peek(0) == '_' || l.peek(0) == '$') {
+				l.next()
+			}
+			l.emit(tokenIdent, start, line, col)
+		default:
+			l.next()
+			l.emit(tokenPunct, start, line, col)
+		}
+	}
+}
+
+// lexString consumes a quoted string literal
+func (l *scriptLexer) lexString(quote rune, line, col int) {
+	l.next()
+	for l.pos < len(l.src) {
+		switch l.peek(0) {
+		case '\\':
+			l.next()
+			if l.pos < len(l.src) {
+				l.next()
+			}
+		case '\n':
+			l.errorf(line, col, "unterminated string literal")
+			return
+		case quote:
+			l.next()
+			return
+		default:
+			l.next()
+		}
+	}
+	l.errorf(line, col, "unterminated string literal")
+}
+
+// lexTemplate consumes a template literal
+func (l *scriptLexer) lexTemplate(line, col int) {
+	l.next()
+	for l.pos < len(l.src) {
+		switch l.next() {
+		case '\\':
+			if l.pos < len(l.src) {
+				l.next()
+			}
+		case '`':
+			return
+		}
+	}
+	l.errorf(line, col, "unterminated template literal")
+}
+
+// lexRegexp consumes a regular expression literal and its flags
+func (l *scriptLexer) lexRegexp(line, col int) {
+	l.next()
+	inClass := false
+	for l.pos < len(l.src) {
+		switch l.peek(0) {
+		case '\\':
+			l.next()
+			if l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.next()
+			}
+		case '\n':
+			l.errorf(line, col, "unterminated regular expression")
+			return
+		case '[':
+			inClass = true
+			l.next()
+		case ']':
+			inClass = false
+			l.next()
+		case '/':
+			l.next()
+			if !inClass {
+				for l.pos < len(l.src) && unicode.IsLetter(l.peek(0)) {
+					l.next()
+				}
+				return
+			}
+		default:
+			l.next()
+		}
+	}
+	l.errorf(line, col, "unterminated regular expression")
+}
+
+// closers maps each opening bracket to its closing bracket
+var closers = map[string]string{"(": ")", "[": "]", "{": "}"}
+
+// checkBrackets reports unbalanced brackets
+func checkBrackets(tokens []scriptToken) ScriptDiagnostics {
+	var diagnostics ScriptDiagnostics
+	var stack []scriptToken
+	for _, token := range tokens {
+		if token.kind != tokenPunct {
+			continue
+		}
+		switch token.text {
+		case "(", "[", "{":
+			stack = append(stack, token)
+		case ")", "]", "}":
+			if len(stack) == 0 || closers[stack[len(stack)-1].text] != token.text {
+				diagnostics = append(diagnostics, ScriptDiagnostic{
+					Line:     token.line,
+					Column:   token.col,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("unexpected '%s'", token.text),
+				})
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, token := range stack {
+		diagnostics = append(diagnostics, ScriptDiagnostic{
+			Line:     token.line,
+			Column:   token.col,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("unclosed '%s'", token.text),
+		})
+	}
+	return diagnostics
+}
+
+// checkTestCalls reports calls to unknown 'test' methods and problems with
+// the transaction and step structure
+func checkTestCalls(tokens []scriptToken) ScriptDiagnostics {
+	var diagnostics ScriptDiagnostics
+	report := func(token scriptToken, severity, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, ScriptDiagnostic{
+			Line:     token.line,
+			Column:   token.col,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	var transaction, step *scriptToken
+	var sawTransaction bool
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].kind != tokenIdent || tokens[i].text != "test" ||
+			tokens[i+1].text != "." || tokens[i+2].kind != tokenIdent || tokens[i+3].text != "(" {
+			continue
+		}
+		if i > 0 && tokens[i-1].text == "." {
+			continue
+		}
+		call := tokens[i]
+		method := tokens[i+2].text
+		if !ValidScriptAPIMethod(method) {
+			report(call, SeverityWarning, "unknown API call test.%s", method)
+			continue
+		}
+		switch method {
+		case "beginTransaction":
+			if transaction != nil {
+				report(call, SeverityError, "test.beginTransaction called while the transaction started on line %d is still open", transaction.line)
+				continue
+			}
+			transaction = &tokens[i]
+			sawTransaction = true
+		case "endTransaction":
+			if transaction == nil {
+				report(call, SeverityError, "test.endTransaction called without test.beginTransaction")
+				continue
+			}
+			if step != nil {
+				report(call, SeverityWarning, "step started on line %d is not ended before test.endTransaction", step.line)
+				step = nil
+			}
+			transaction = nil
+		case "beginStep":
+			if transaction == nil {
+				report(call, SeverityError, "test.beginStep called outside of a transaction")
+			}
+			if step != nil {
+				report(call, SeverityWarning, "step started on line %d is not ended before the next test.beginStep", step.line)
+			}
+			step = &tokens[i]
+		case "endStep":
+			if step == nil {
+				report(call, SeverityError, "test.endStep called without test.beginStep")
+				continue
+			}
+			step = nil
+		}
+	}
+
+	if !sawTransaction {
+		report(scriptToken{line: 1, col: 1}, SeverityError, "script does not call test.beginTransaction")
+	}
+	if transaction != nil {
+		report(*transaction, SeverityError, "transaction is never ended with test.endTransaction")
+	}
+	return diagnostics
+}
@@ -0,0 +1,75 @@
+package neustar
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateScript
+func TestValidateScript(t *testing.T) {
+	t.Parallel()
+
+	valid := `var c = test.openBrowser();
+test.beginTransaction();
+test.beginStep("Home", 30000);
+// a comment with an unmatched ( bracket
+c.get("http://example.com/?q=(");
+var re = /\d+\/[)]/g;
+test.endStep();
+test.endTransaction();
+`
+	if d := ValidateScript(valid); len(d) != 0 {
+		t.Errorf("expected no diagnostics, got:\n%s", d)
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "missing transaction",
+			script: "var c = test.openBrowser();\nc.get(\"http://example.com\");\n",
+			want:   []string{"1:1: error: script does not call test.beginTransaction"},
+		},
+		{
+			name:   "unterminated transaction",
+			script: "test.beginTransaction();\ntest.beginStep(\"a\");\ntest.endStep();\n",
+			want:   []string{"1:1: error: transaction is never ended"},
+		},
+		{
+			name:   "unbalanced steps",
+			script: "test.beginTransaction();\ntest.endStep();\ntest.beginStep(\"a\");\ntest.endTransaction();\n",
+			want: []string{
+				"2:1: error: test.endStep called without test.beginStep",
+				"4:1: warning: step started on line 3 is not ended",
+			},
+		},
+		{
+			name:   "unknown call",
+			script: "test.beginTransaction();\ntest.clickOn(\"#buy\");\ntest.endTransaction();\n",
+			want:   []string{"2:1: warning: unknown API call test.clickOn"},
+		},
+		{
+			name:   "syntax errors",
+			script: "test.beginTransaction();\nc.get(\"http://example.com);\nif (x) {\ntest.endTransaction();\n",
+			want: []string{
+				"2:7: error: unterminated string literal",
+				"2:6: error: unclosed '('",
+				"3:8: error: unclosed '{'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		d := ValidateScript(tt.script)
+		if !d.HasErrors() && strings.Contains(strings.Join(tt.want, ""), "error") {
+			t.Errorf("%s: expected errors, got:\n%s", tt.name, d)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(d.String(), want) {
+				t.Errorf("%s: expected diagnostic %q, got:\n%s", tt.name, want, d)
+			}
+		}
+	}
+}
@@ -1 +1,263 @@
 package neustar
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewScriptValidationReport
+func TestNewScriptValidationReport(t *testing.T) {
+	t.Parallel()
+
+	scripts := []ScriptDetail{
+		{
+			ID:            "abc123",
+			Name:          "checkout",
+			LatestVersion: "3",
+			ValidationErrors: []ScriptValidationError{
+				{LineNumber: 12, Message: "element #buy not found"},
+			},
+		},
+		{ID: "def456", Name: "login"},
+	}
+
+	report := NewScriptValidationReport(scripts)
+	if len(report.Scripts) != 2 {
+		t.Fatalf("expected 2 scripts in report, got %d", len(report.Scripts))
+	}
+
+	out := report.String()
+	for _, want := range []string{"2 invalid script(s)", "checkout (abc123) version 3", "line 12: element #buy not found", "no reason given"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestScriptingCreate
+func TestScriptingCreate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+		call func(s *Scripting) (Script, error)
+		body map[string]string
+	}{
+		{
+			"Create", "/script/1.0",
+			func(s *Scripting) (Script, error) {
+				return s.Create(&ScriptCreateParameters{Name: "home", Body: "test.beginTransaction();"})
+			},
+			map[string]string{"name": "home", "scriptBody": "test.beginTransaction();"},
+		},
+		{
+			"CreateFromURL", "/script/1.0/url",
+			func(s *Scripting) (Script, error) {
+				return s.CreateFromURL(&ScriptCreateFromURLParameters{Name: "home", URL: "https://example.com/"})
+			},
+			map[string]string{"name": "home", "url": "https://example.com/"},
+		},
+		{
+			"UploadBody", "/script/1.0/upload/body",
+			func(s *Scripting) (Script, error) {
+				return s.UploadBody(&ScriptCreateParameters{Name: "home", Body: "test.beginTransaction();"})
+			},
+			map[string]string{"name": "home", "scriptBody": "test.beginTransaction();"},
+		},
+	}
+	for _, test := range tests {
+		n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Method != "POST" || r.URL.Path != test.path || r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("%s: unexpected request %s %s", test.name, r.Method, r.URL)
+			}
+			for k, v := range test.body {
+				if body[k] != v {
+					t.Errorf("%s: expected %s to be %q, got %q", test.name, k, v, body[k])
+				}
+			}
+			w.Write([]byte(`{"data": {"items": {"id": "s1", "version": "1"}}}`))
+		})
+		script, err := test.call(NewScript(n))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if script != (Script{ID: "s1", Version: "1", Name: "home"}) {
+			t.Errorf("%s: unexpected script %+v", test.name, script)
+		}
+	}
+}
+
+// TestScriptingCreateError
+func TestScriptingCreateError(t *testing.T) {
+	t.Parallel()
+
+	responses := map[int]string{
+		http.StatusBadRequest: `{"error": {"code": "MON_0003", "message": "name is/are required fields"}}`,
+		http.StatusOK:         `{"data": {"items": {}}}`,
+	}
+	for status, body := range responses {
+		n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+		script, err := NewScript(n).Create(&ScriptCreateParameters{Name: "home"})
+		if err == nil || script != (Script{}) {
+			t.Errorf("%d: expected an error and no script, got %+v", status, script)
+		}
+	}
+}
+
+// TestScriptingCreateNilParameters
+func TestScriptingCreateNilParameters(t *testing.T) {
+	t.Parallel()
+
+	s := NewScript(NewNeustar("key", "secret"))
+	if _, err := s.Create(nil); err != errScriptParametersRequired {
+		t.Errorf("Create: expected errScriptParametersRequired, got %v", err)
+	}
+	if _, err := s.CreateFromURL(nil); err != errScriptParametersRequired {
+		t.Errorf("CreateFromURL: expected errScriptParametersRequired, got %v", err)
+	}
+	if _, err := s.UploadBody(nil); err != errScriptParametersRequired {
+		t.Errorf("UploadBody: expected errScriptParametersRequired, got %v", err)
+	}
+}
+
+// TestUploadTestScriptFile
+func TestUploadTestScriptFile(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/script/1.0/upload/file" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		if header.Filename != "home.js" || string(content) != "test.beginTransaction();" {
+			t.Errorf("unexpected file part %q: %q", header.Filename, content)
+		}
+		if name := r.FormValue("name"); name != "home" {
+			t.Errorf("expected name field to be home, got %q", name)
+		}
+		w.Write([]byte(`{"data": {"items": {"id": "s1", "version": "1"}}}`))
+	})
+	script, err := NewScript(n).UploadTestScriptFile("home.js", strings.NewReader("test.beginTransaction();"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.ID != "s1" {
+		t.Errorf("expected script s1, got %+v", script)
+	}
+}
+
+// TestUploadTestScriptFileFromPath
+func TestUploadTestScriptFileFromPath(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "home.js")
+	if err := os.WriteFile(path, []byte("test.beginTransaction();"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		if header.Filename != "home.js" || string(content) != "test.beginTransaction();" {
+			t.Errorf("unexpected file part %q: %q", header.Filename, content)
+		}
+		w.Write([]byte(`{"data": {"items": {"id": "s1", "version": "1"}}}`))
+	})
+	if _, err := NewScript(n).UploadTestScriptFileFromPath(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewScript(n).UploadTestScriptFileFromPath(filepath.Join(t.TempDir(), "missing.js")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+// TestUploadTestScriptFileError
+func TestUploadTestScriptFileError(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"code": "MON_0003", "message": "file is/are required fields"}}`))
+	})
+	if _, err := NewScript(n).UploadTestScriptFile("home.js", strings.NewReader("")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+// TestCloneTestScriptFile
+func TestCloneTestScriptFile(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/script/1.0/s1/clone" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "home copy" {
+			t.Errorf("expected name to be %q, got %q", "home copy", body["name"])
+		}
+		w.Write([]byte(`{"data": {"items": {"id": "s2", "version": "1"}}}`))
+	})
+	script, err := NewScript(n).CloneTestScriptFile("s1", "home copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script != (Script{ID: "s2", Version: "1", Name: "home copy"}) {
+		t.Errorf("unexpected script %+v", script)
+	}
+}
+
+// TestScriptingGetNotFound
+func TestScriptingGetNotFound(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/script/1.0/s1" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": "MON_0004", "message": "script not found"}}`))
+	})
+	_, err := NewScript(n).Get("s1")
+	var re *ResponseError
+	if !errors.As(err, &re) || re.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 ResponseError, got %v", err)
+	}
+}
+
+// TestScriptingGetIDRequired
+func TestScriptingGetIDRequired(t *testing.T) {
+	t.Parallel()
+
+	s := NewScript(NewNeustar("key", "secret"))
+	if _, err := s.Get(""); err != errScriptIDRequired {
+		t.Errorf("Get: expected errScriptIDRequired, got %v", err)
+	}
+	if _, err := s.GetVersion("", "1"); err != errScriptIDRequired {
+		t.Errorf("GetVersion: expected errScriptIDRequired, got %v", err)
+	}
+}
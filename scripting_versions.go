@@ -0,0 +1,250 @@
+package neustar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// ScriptUpdateParameters holds the parameters passed in to update a script
+type ScriptUpdateParameters struct {
+	// The name of the script
+	Name string `json:"name,omitempty"`
+
+	// A description of what the script does
+	Description string `json:"description,omitempty"`
+
+	// The JavaScript body of the script. A new version is created when
+	// the body changes.
+	Body string `json:"scriptBody,omitempty"`
+}
+
+// ListVersions retrieves the version history of the script with the given ID
+func (s *Scripting) ListVersions(id string) ([]ScriptVersion, error) {
+	if id == "" {
+		return nil, errScriptIDRequired
+	}
+	var response *http.Response
+	var data map[string]map[string][]ScriptVersion
+	response, err := http.Get(fmt.Sprintf(
+		"%s%s/%s%s?apikey=%s&sig=%s",
+		s.neustar.baseURL(), ScriptURI, id, VersionURI, s.neustar.Key, s.neustar.DigitalSignature()))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if err := checkResponse(response); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data["data"]["items"], nil
+}
+
+// GetVersionBody retrieves the body of the given version of a script
+func (s *Scripting) GetVersionBody(id, version string) (string, error) {
+	if id == "" {
+		return "", errScriptIDRequired
+	}
+	script, err := s.GetVersion(id, version)
+	if err != nil {
+		return "", err
+	}
+	return script.ScriptBody, nil
+}
+
+// Update changes some or all of the parameters of an existing script and
+// returns the ID and version of the script after the update.
+func (s *Scripting) Update(id string, sup *ScriptUpdateParameters) (Script, error) {
+	if id == "" {
+		return Script{}, errScriptIDRequired
+	}
+	buffer, err := json.Marshal(sup)
+	if err != nil {
+		return Script{}, err
+	}
+	body := bytes.NewBuffer(buffer)
+	request, err := http.NewRequest(
+		"PUT",
+		fmt.Sprintf("%s%s/%s?apikey=%s&sig=%s", s.neustar.baseURL(), ScriptURI, id, s.neustar.Key, s.neustar.DigitalSignature()),
+		body)
+	if err != nil {
+		return Script{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	response, err := client.Do(request)
+	if err != nil {
+		return Script{}, err
+	}
+	defer response.Body.Close()
+	return decodeScript(response)
+}
+
+// Rollback uploads the body of the given version of a script as its new
+// latest version. Earlier versions are kept in the history. A version
+// without a body is an error, as the API would leave the script unchanged.
+func (s *Scripting) Rollback(id, version string) (Script, error) {
+	if id == "" {
+		return Script{}, errScriptIDRequired
+	}
+	body, err := s.GetVersionBody(id, version)
+	if err != nil {
+		return Script{}, err
+	}
+	if body == "" {
+		return Script{}, fmt.Errorf("neustar: version %s of script %s has no body", version, id)
+	}
+	return s.Update(id, &ScriptUpdateParameters{Body: body})
+}
+
+// DiffVersions returns a unified diff between two versions of a script. An
+// empty string is returned when the versions are identical.
+func (s *Scripting) DiffVersions(id, fromVersion, toVersion string) (string, error) {
+	if id == "" {
+		return "", errScriptIDRequired
+	}
+	from, err := s.GetVersionBody(id, fromVersion)
+	if err != nil {
+		return "", err
+	}
+	to, err := s.GetVersionBody(id, toVersion)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(
+		fmt.Sprintf("%s@%s", id, fromVersion),
+		fmt.Sprintf("%s@%s", id, toVersion),
+		from, to), nil
+}
+
+// diffOp is a single line of an edit script
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the unified diff between a and b
+func unifiedDiff(aName, bName, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for i := 0; i < len(changes); {
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContext {
+			j++
+		}
+		start := changes[i] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changes[j] + diffContext + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+		writeHunk(&out, ops, start, end)
+		i = j + 1
+	}
+	return out.String()
+}
+
+// writeHunk writes the ops in [start, end) as a single hunk
+func writeHunk(out *strings.Builder, ops []diffOp, start, end int) {
+	var aStart, bStart, aLen, bLen int
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			aStart++
+		}
+		if op.kind != '-' {
+			bStart++
+		}
+	}
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	if aLen > 0 {
+		aStart++
+	}
+	if bLen > 0 {
+		bStart++
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+	for _, op := range ops[start:end] {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// diffLines computes the edit script turning a into b from the longest
+// common subsequence of their lines
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits the given text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+}
@@ -0,0 +1,132 @@
+package neustar
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// TestUnifiedDiff
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	a := "var c = test.openBrowser();\ntest.beginTransaction();\ntest.beginStep(\"home\");\nc.get(\"http://example.com\");\ntest.endStep();\ntest.endTransaction();\n"
+	b := "var c = test.openBrowser();\ntest.beginTransaction();\ntest.beginStep(\"home\");\nc.get(\"https://example.com\");\ntest.endStep();\ntest.endTransaction();\n"
+
+	want := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n" +
+		" var c = test.openBrowser();\n" +
+		" test.beginTransaction();\n" +
+		" test.beginStep(\"home\");\n" +
+		"-c.get(\"http://example.com\");\n" +
+		"+c.get(\"https://example.com\");\n" +
+		" test.endStep();\n" +
+		" test.endTransaction();\n"
+
+	if got := unifiedDiff("a", "b", a, b); got != want {
+		t.Errorf("unexpected diff, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := unifiedDiff("a", "b", a, a); got != "" {
+		t.Errorf("expected no diff for identical input, got:\n%s", got)
+	}
+
+	if got := unifiedDiff("a", "b", "", "one\n"); got != "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+one\n" {
+		t.Errorf("unexpected diff against empty input, got:\n%s", got)
+	}
+}
+
+// TestListVersions
+func TestListVersions(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/script/1.0/s1/version" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": "MON_0004", "message": "script not found"}}`))
+	})
+	s := NewScript(n)
+	if _, err := s.ListVersions("s1"); err == nil {
+		t.Error("expected an error for a 404 response")
+	}
+	if _, err := s.ListVersions(""); err != errScriptIDRequired {
+		t.Errorf("expected errScriptIDRequired, got %v", err)
+	}
+}
+
+// TestScriptingUpdate
+func TestScriptingUpdate(t *testing.T) {
+	t.Parallel()
+
+	responses := map[int]string{
+		http.StatusInternalServerError: `{"error": {"code": "MON_9999", "message": "internal error"}}`,
+		http.StatusOK:                  `{"data": {"items": {"version": "2"}}}`,
+	}
+	for status, body := range responses {
+		n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PUT" || r.URL.Path != "/script/1.0/s1" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+		if _, err := NewScript(n).Update("s1", &ScriptUpdateParameters{Body: "x"}); err == nil {
+			t.Errorf("%d: expected an error", status)
+		}
+	}
+	if _, err := NewScript(&Neustar{}).Update("", &ScriptUpdateParameters{}); err != errScriptIDRequired {
+		t.Errorf("expected errScriptIDRequired, got %v", err)
+	}
+}
+
+// TestRollback
+func TestRollback(t *testing.T) {
+	t.Parallel()
+
+	var updated string
+	n := newTestNeustar(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/script/1.0/s1/version/1":
+			w.Write([]byte(`{"data": {"items": {"id": "s1", "scriptBody": "v1"}}}`))
+		case r.Method == "GET" && r.URL.Path == "/script/1.0/s1/version/2":
+			w.Write([]byte(`{"data": {"items": {"id": "s1"}}}`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": "MON_0004", "message": "version not found"}}`))
+		case r.Method == "PUT" && r.URL.Path == "/script/1.0/s1":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			updated = body["scriptBody"]
+			w.Write([]byte(`{"data": {"items": {"id": "s1", "version": "3"}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	s := NewScript(n)
+	script, err := s.Rollback("s1", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.Version != "3" || updated != "v1" {
+		t.Errorf("expected version 3 with body v1, got %+v with body %q", script, updated)
+	}
+
+	updated = ""
+	if _, err := s.Rollback("s1", "9"); err == nil || updated != "" {
+		t.Errorf("expected a missing version to fail without an update, got %v", err)
+	}
+	if _, err := s.Rollback("s1", "2"); err == nil || updated != "" {
+		t.Errorf("expected an empty version to fail without an update, got %v", err)
+	}
+	if _, err := s.Rollback("", "1"); err != errScriptIDRequired {
+		t.Errorf("expected errScriptIDRequired, got %v", err)
+	}
+	if _, err := s.GetVersionBody("", "1"); err != errScriptIDRequired {
+		t.Errorf("GetVersionBody: expected errScriptIDRequired, got %v", err)
+	}
+	if _, err := s.DiffVersions("", "1", "2"); err != errScriptIDRequired {
+		t.Errorf("DiffVersions: expected errScriptIDRequired, got %v", err)
+	}
+}